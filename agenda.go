@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// --- AGENDA ---
+
+// The agenda shows the tasks of the task list that have a due date, sorted
+// by it and grouped into Overdue, Today, Tomorrow, This week and Later. The
+// calendar shows how many tasks are due on each day of a month, and lists
+// the tasks of a day in the task list. Weeks start on Monday.
+
+// agendaOrder is how the agenda is sorted and grouped.
+var agendaOrder = taskOrder{Sort: "due", Group: "due"}
+
+// dueGroup is the agenda group of a task, with days counted in the time zone
+// of now.
+func dueGroup(task Task, now time.Time) taskGroup {
+	due, ok := msTime(task.DueDate)
+	if !ok {
+		return taskGroup{name: "No due date", rank: 5}
+	}
+	_, days := relativeDate(due, now)
+	// Days left in the week after today, with Sunday the last day.
+	weekLeft := (7 - int(now.Weekday())) % 7
+	switch {
+	case days < 0:
+		return taskGroup{name: "Overdue", rank: 0}
+	case days == 0:
+		return taskGroup{name: "Today", rank: 1}
+	case days == 1:
+		return taskGroup{name: "Tomorrow", rank: 2}
+	case days <= weekLeft:
+		return taskGroup{name: "This week", rank: 3}
+	}
+	return taskGroup{name: "Later", rank: 4}
+}
+
+// startOfDay is midnight of the day of t.
+func startOfDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
+
+// dueOn reports whether a task is due on the day starting at day.
+func dueOn(task Task, day time.Time) bool {
+	due, ok := msTime(task.DueDate)
+	return ok && startOfDay(due.In(day.Location())).Equal(day)
+}
+
+// toggleAgenda switches the task list between the agenda and the saved
+// order.
+func (m model) toggleAgenda() (tea.Model, tea.Cmd) {
+	m.agenda = !m.agenda
+	m.calendarDay = time.Time{}
+	m.showTasks()
+	m.list.Title = m.taskListTitle()
+	return m, m.fetchSelectedCounts()
+}
+
+// --- UPDATE & VIEW (CALENDAR) ---
+
+func (m model) startCalendar() model {
+	m.state = calendarView
+	if m.calendarDay.IsZero() {
+		m.calendarDay = startOfDay(m.datesNow())
+	}
+	return m
+}
+
+func updateCalendar(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "left", "h":
+			m.calendarDay = m.calendarDay.AddDate(0, 0, -1)
+		case "right", "l":
+			m.calendarDay = m.calendarDay.AddDate(0, 0, 1)
+		case "up", "k":
+			m.calendarDay = m.calendarDay.AddDate(0, 0, -7)
+		case "down", "j":
+			m.calendarDay = m.calendarDay.AddDate(0, 0, 7)
+		case "[", "pgup":
+			m.calendarDay = m.calendarDay.AddDate(0, -1, 0)
+		case "]", "pgdown":
+			m.calendarDay = m.calendarDay.AddDate(0, 1, 0)
+		case ".":
+			m.calendarDay = startOfDay(m.datesNow())
+		case "enter":
+			m.state = listView
+			m.agenda = false
+			m.showTasks()
+			m.list.Title = m.taskListTitle()
+			return m, m.fetchSelectedCounts()
+		case "esc", "q":
+			m.state = listView
+			m.calendarDay = time.Time{}
+			m.showTasks()
+			m.list.Title = m.taskListTitle()
+			return m, m.fetchSelectedCounts()
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewCalendar() string {
+	day := m.calendarDay
+	first := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	today := startOfDay(m.datesNow())
+
+	counts := make(map[int]int)
+	for _, task := range m.tasks {
+		if due, ok := msTime(task.DueDate); ok {
+			due = due.In(day.Location())
+			if due.Year() == day.Year() && due.Month() == day.Month() {
+				counts[due.Day()]++
+			}
+		}
+	}
+
+	h, _ := appStyle.GetFrameSize()
+	cell := min(max((m.width-h)/7, 6), 12)
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s · %s", m.scope.name, first.Format("January 2006"))))
+	b.WriteString("\n\n")
+	for _, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		b.WriteString(blurredStyle.Render(fmt.Sprintf("%-*s", cell, name)))
+	}
+	b.WriteString("\n")
+
+	// Monday is the first column.
+	offset := (int(first.Weekday()) + 6) % 7
+	b.WriteString(strings.Repeat(" ", offset*cell))
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		label := fmt.Sprintf("%2d", d.Day())
+		if n := counts[d.Day()]; n > 0 {
+			label += fmt.Sprintf(" ●%d", n)
+		}
+		style := lipgloss.NewStyle()
+		switch {
+		case d.Equal(day):
+			style = style.Reverse(true)
+		case d.Equal(today):
+			style = focusedStyle.Bold(true)
+		case counts[d.Day()] > 0 && d.Before(today):
+			style = failureStyle
+		case counts[d.Day()] > 0:
+			style = style.Foreground(lipgloss.Color(theme.Accent))
+		}
+		b.WriteString(style.Render(label) + strings.Repeat(" ", max(cell-lipgloss.Width(label), 1)))
+		if d.Weekday() == time.Sunday {
+			b.WriteString("\n\n")
+		}
+	}
+
+	// The tasks due on the selected day.
+	var due []Task
+	for _, task := range m.tasks {
+		if dueOn(task, day) {
+			due = append(due, task)
+		}
+	}
+	b.WriteString("\n\n" + day.Format("Monday, January 2") + "\n")
+	if len(due) == 0 {
+		b.WriteString(blurredStyle.Render("  Nothing due") + "\n")
+	}
+	const shown = 5
+	for i, task := range due {
+		if i == shown {
+			b.WriteString(blurredStyle.Render(fmt.Sprintf("  … and %d more", len(due)-shown)) + "\n")
+			break
+		}
+		b.WriteString("  " + statusPill(task, 0) + " " + task.Name + "\n")
+	}
+	b.WriteString(helpStyle.Render("\n←↓↑→: move • [/]: month • .: today • enter: list the day's tasks • esc: back"))
+	return appStyle.Render(b.String())
+}
+
+// --- AGENDA (CLI) ---
+
+var (
+	agendaWhere string
+	agendaView  string
+)
+
+var agendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "Print your tasks by due date",
+	Long: `Print the tasks with a due date grouped into Overdue, Today, Tomorrow, This
+week and Later, in the time zone of your ClickUp profile. Without --where or
+--view, these are the tasks assigned to you.`,
+	Example: `  clup agenda
+  clup agenda --where 'space=90123456 tag=release'
+  clup agenda --view standup`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if agendaWhere != "" && agendaView != "" {
+			fmt.Println("Only one of --where or --view can be given.")
+			os.Exit(1)
+		}
+		apiToken, teamID := requireCredentials()
+		where := "assignee=me"
+		switch {
+		case agendaWhere != "":
+			where = agendaWhere
+		case agendaView != "":
+			name, ok := findView(agendaView)
+			if !ok {
+				fmt.Printf("No view named %q, see clup views.\n", agendaView)
+				os.Exit(1)
+			}
+			where = settings.Views[name].Filter
+		}
+		members := &memberResolver{apiToken: apiToken, teamID: teamID}
+		tasks, err := queryTasks(apiToken, teamID, where, members)
+		if err != nil {
+			fmt.Println("Error fetching tasks:", err)
+			os.Exit(1)
+		}
+		loc, err := members.location()
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		printAgenda(tasks, time.Now().In(loc))
+	},
+}
+
+// printAgenda prints the agenda groups, each as its own table, with dates in
+// the time zone of now.
+func printAgenda(tasks []Task, now time.Time) {
+	var dated []Task
+	for _, task := range tasks {
+		if task.DueDate != "" {
+			dated = append(dated, task)
+		}
+	}
+	if len(dated) == 0 {
+		fmt.Println("Nothing due.")
+		return
+	}
+	heading := lipgloss.NewStyle().Bold(true)
+	var w *tabwriter.Writer
+	for i, item := range taskItems(dated, agendaOrder, now) {
+		switch item := item.(type) {
+		case taskGroupHeader:
+			if w != nil {
+				w.Flush()
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(heading.Render(fmt.Sprintf("%s (%d)", item.name, item.count)))
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		case Task:
+			due := taskDateOf(item.DueDate, now.Location())
+			when := due.time.Format("Mon Jan 2")
+			if due.hasTime {
+				when += due.time.Format(" 15:04")
+			}
+			if label, days := relativeDate(due.time, now); days < 0 {
+				when += " (" + label + ")"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", item.ID, when, item.Status.Status, truncate(item.Name, 60), taskLocation(item))
+		}
+	}
+	w.Flush()
+}
+
+func init() {
+	agendaCmd.Flags().StringVar(&agendaWhere, "where", "", "query selecting the tasks, as for task update")
+	agendaCmd.Flags().StringVar(&agendaView, "view", "", "show the tasks of this saved view")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dueAt is a task due at t.
+func dueAt(id string, t time.Time) Task {
+	return Task{ID: id, Name: "Task " + id, DueDate: strconv.FormatInt(t.UnixMilli(), 10)}
+}
+
+func TestDueGroup(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	now := time.Date(2026, time.March, 4, 10, 0, 0, 0, ny) // a Wednesday
+	due := func(d int) Task {
+		return dueAt("", time.Date(2026, time.March, d, 12, 0, 0, 0, ny))
+	}
+	tests := []struct {
+		task Task
+		want string
+	}{
+		{Task{}, "No due date"},
+		{due(3), "Overdue"},
+		{due(4), "Today"},
+		{due(5), "Tomorrow"},
+		{due(8), "This week"},
+		{due(9), "Later"},
+	}
+	for _, tt := range tests {
+		if got := dueGroup(tt.task, now).name; got != tt.want {
+			t.Errorf("dueGroup(%s) = %q, want %q", tt.task.DueDate, got, tt.want)
+		}
+	}
+}
+
+func TestAgendaInUserTimeZone(t *testing.T) {
+	ny, err1 := time.LoadLocation("America/New_York")
+	tokyo, err2 := time.LoadLocation("Asia/Tokyo")
+	if err1 != nil || err2 != nil {
+		t.Skip(err1, err2)
+	}
+	// 11:00 on Wednesday in New York is 1:00 on Thursday in Tokyo.
+	now := time.Date(2026, time.March, 4, 16, 0, 0, 0, time.UTC)
+	// Due at 9:00 on Wednesday in New York, 23:00 in Tokyo.
+	task := dueAt("1", time.Date(2026, time.March, 4, 14, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		loc   *time.Location
+		group string
+		when  string
+	}{
+		{ny, "Today", "Wed Mar 4 09:00"},
+		{tokyo, "Overdue", "Wed Mar 4 23:00 (yesterday)"},
+	}
+	for _, tt := range tests {
+		if got := dueGroup(task, now.In(tt.loc)).name; got != tt.group {
+			t.Errorf("dueGroup in %s = %q, want %q", tt.loc, got, tt.group)
+		}
+		items := itemNames(taskItems([]Task{task}, agendaOrder, now.In(tt.loc)))
+		if len(items) == 0 || items[0] != "# "+tt.group+" (1)" {
+			t.Errorf("agenda in %s = %q, want the task under %s", tt.loc, items, tt.group)
+		}
+		out := printed(t, "", func() { printAgenda([]Task{task}, now.In(tt.loc)) })
+		if !strings.Contains(out, tt.group+" (1)") || !strings.Contains(out, tt.when) {
+			t.Errorf("printAgenda in %s:\n%s\nwant the task under %s, due %s", tt.loc, out, tt.group, tt.when)
+		}
+	}
+}
+
+func TestTaskListUsesUserTimeZone(t *testing.T) {
+	m := newModel("pk_1", "1", "", false)
+	m.width, m.height = 80, 40
+	m, _ = m.startTaskList(taskScope{kind: scopeList, id: "900", name: "Inbox"})
+	m = send(m, taskListMsg{dueAt("1", time.Now())})
+	if m.currentUser != nil {
+		t.Fatalf("user set before it was loaded")
+	}
+	m = send(m, listUserMsg{Username: "ann", Timezone: "Asia/Tokyo"})
+	if loc := m.datesNow().Location().String(); loc != "Asia/Tokyo" {
+		t.Errorf("task list dates in %s, want Asia/Tokyo", loc)
+	}
+	if m = send(m, listUserMsg{Timezone: "Europe/Paris"}); m.currentUser.Timezone != "Asia/Tokyo" {
+		t.Errorf("a later user replaced the loaded one")
+	}
+}
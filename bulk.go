@@ -0,0 +1,564 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- BULK OPERATIONS ---
+
+var failureStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5f5f"))
+
+type bulkKind int
+
+const (
+	bulkSetStatus bulkKind = iota
+	bulkAssign
+	bulkUnassign
+	bulkSetPriority
+	bulkMove
+	bulkAddTag
+	bulkRemoveTag
+	bulkDelete
+)
+
+// bulkOp describes a single change that is applied to every task of a
+// multi-selection.
+type bulkOp struct {
+	kind      bulkKind
+	status    string
+	assignees []int
+	priority  int
+	listID    string
+	listName  string
+	tag       string
+}
+
+func (op bulkOp) String() string {
+	switch op.kind {
+	case bulkSetStatus:
+		return fmt.Sprintf("Setting status to %q", op.status)
+	case bulkAssign:
+		return fmt.Sprintf("Assigning %d member(s)", len(op.assignees))
+	case bulkUnassign:
+		return fmt.Sprintf("Unassigning %d member(s)", len(op.assignees))
+	case bulkSetPriority:
+		return "Setting priority"
+	case bulkMove:
+		return fmt.Sprintf("Moving to %s", op.listName)
+	case bulkAddTag:
+		return fmt.Sprintf("Adding tag %q", op.tag)
+	case bulkRemoveTag:
+		return fmt.Sprintf("Removing tag %q", op.tag)
+	case bulkDelete:
+		return "Deleting"
+	}
+	return "Updating"
+}
+
+type bulkResult struct {
+	task Task
+	err  error
+}
+
+type bulkResultMsg bulkResult
+
+// runBulkOp applies op to a single task and reports the API error, if any.
+func runBulkOp(apiToken, teamID string, op bulkOp, task Task) error {
+	var cmd tea.Cmd
+	switch op.kind {
+	case bulkSetStatus:
+		cmd = updateTaskCmd(apiToken, task.ID, "", op.status)
+	case bulkAssign:
+		cmd = updateTaskFieldsCmd(apiToken, task.ID, map[string]interface{}{
+			"assignees": map[string][]int{"add": op.assignees},
+		})
+	case bulkUnassign:
+		cmd = updateTaskFieldsCmd(apiToken, task.ID, map[string]interface{}{
+			"assignees": map[string][]int{"rem": op.assignees},
+		})
+	case bulkSetPriority:
+		var priority interface{}
+		if op.priority > 0 {
+			priority = op.priority
+		}
+		cmd = updateTaskFieldsCmd(apiToken, task.ID, map[string]interface{}{"priority": priority})
+	case bulkMove:
+		cmd = moveTaskCmd(apiToken, teamID, task.ID, op.listID)
+	case bulkAddTag:
+		cmd = addTagCmd(apiToken, task.ID, op.tag)
+	case bulkRemoveTag:
+		cmd = removeTagCmd(apiToken, task.ID, op.tag)
+	case bulkDelete:
+		cmd = deleteTaskCmd(apiToken, task.ID)
+	default:
+		return fmt.Errorf("unknown bulk operation")
+	}
+	if err, ok := cmd().(error); ok {
+		return err
+	}
+	return nil
+}
+
+func bulkStepCmd(apiToken, teamID string, op bulkOp, task Task) tea.Cmd {
+	return func() tea.Msg {
+		return bulkResultMsg{task: task, err: runBulkOp(apiToken, teamID, op, task)}
+	}
+}
+
+// --- TASK DELEGATE (MULTI-SELECT) ---
+
+// isMarked reports whether a task is marked or inside the visual range.
+func (d taskDelegate) isMarked(m list.Model, index int, listItem list.Item) bool {
+	task, ok := listItem.(Task)
+	if !ok {
+		return false
+	}
+	if _, exists := d.marked[task.ID]; exists {
+		return true
+	}
+	if d.visualMode {
+		lo, hi := d.visualAnchor, m.Index()
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		return index >= lo && index <= hi
+	}
+	return false
+}
+
+func bulkHelpKeys() []key.Binding {
+	return []key.Binding{
+		keys.Mark,
+		keys.Visual,
+		keys.Status,
+		keys.Assign,
+		keys.Unassign,
+		keys.Priority,
+		keys.Move,
+		keys.Tag,
+		keys.Untag,
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear marks")),
+	}
+}
+
+// refreshTaskDelegate pushes the current selection into the list delegate so
+// marks are redrawn.
+func (m *model) refreshTaskDelegate() {
+	m.list.SetDelegate(newTaskDelegate(m.markedTasks, m.visualMode, m.visualAnchor, m.stats, userLocation(m.currentUser)))
+}
+
+// commitVisualRange turns the pending visual range into regular marks.
+func (m *model) commitVisualRange() {
+	if !m.visualMode {
+		return
+	}
+	if m.markedTasks == nil {
+		m.markedTasks = make(map[string]struct{})
+	}
+	lo, hi := m.visualAnchor, m.list.Index()
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	visible := m.list.VisibleItems()
+	for i := lo; i <= hi && i < len(visible); i++ {
+		if task, ok := visible[i].(Task); ok {
+			m.markedTasks[task.ID] = struct{}{}
+		}
+	}
+	m.visualMode = false
+}
+
+func (m *model) clearMarks() {
+	m.markedTasks = make(map[string]struct{})
+	m.visualMode = false
+	m.refreshTaskDelegate()
+}
+
+func (m model) hasMarks() bool {
+	return len(m.markedTasks) > 0 || m.visualMode
+}
+
+// targetTasks returns the marked tasks in list order, or the task under the
+// cursor when nothing is marked.
+func (m *model) targetTasks() []Task {
+	m.commitVisualRange()
+	var tasks []Task
+	seen := make(map[string]bool)
+	for _, item := range m.list.Items() {
+		task, ok := item.(Task)
+		if !ok || seen[task.ID] {
+			continue
+		}
+		if _, marked := m.markedTasks[task.ID]; marked {
+			seen[task.ID] = true
+			tasks = append(tasks, task)
+		}
+	}
+	if len(tasks) == 0 {
+		if selected, ok := m.list.SelectedItem().(Task); ok {
+			tasks = append(tasks, selected)
+		}
+	}
+	return tasks
+}
+
+// bulkSpaceID is the space whose statuses and lists are offered for the bulk
+// tasks. A view can span spaces, so there it is the space of the first task.
+func (m model) bulkSpaceID() string {
+	if m.scope.kind == scopeView && len(m.bulkTasks) > 0 && m.bulkTasks[0].Space.ID != "" {
+		return m.bulkTasks[0].Space.ID
+	}
+	return m.spaceID
+}
+
+// updateListMarks handles the multi-select and bulk action keys of the task
+// list. It reports whether the key was consumed.
+func updateListMarks(msg tea.KeyMsg, m model) (model, tea.Cmd, bool) {
+	h, v := appStyle.GetFrameSize()
+	switch {
+	case key.Matches(msg, keys.Mark):
+		selected, ok := m.list.SelectedItem().(Task)
+		if !ok {
+			return m, nil, true
+		}
+		if m.markedTasks == nil {
+			m.markedTasks = make(map[string]struct{})
+		}
+		if _, exists := m.markedTasks[selected.ID]; exists {
+			delete(m.markedTasks, selected.ID)
+		} else {
+			m.markedTasks[selected.ID] = struct{}{}
+		}
+		previous := m.list.Index()
+		m.list.CursorDown()
+		m.skipGroupHeader(previous)
+		m.refreshTaskDelegate()
+		return m, nil, true
+	case key.Matches(msg, keys.Visual):
+		if m.visualMode {
+			m.commitVisualRange()
+		} else {
+			m.visualMode = true
+			m.visualAnchor = m.list.Index()
+		}
+		m.refreshTaskDelegate()
+		return m, nil, true
+	case msg.String() == "esc":
+		if !m.hasMarks() {
+			return m, nil, false
+		}
+		m.clearMarks()
+		return m, nil, true
+	case key.Matches(msg, keys.Status):
+		m.bulkTasks = m.targetTasks()
+		if len(m.bulkTasks) == 0 {
+			return m, nil, true
+		}
+		m.state = bulkStatusView
+		m.statusList = newList([]list.Item{}, statusDelegate{}, m.width-h, m.height-v)
+		m.statusList.Title = fmt.Sprintf("Set status of %d task(s)", len(m.bulkTasks))
+		m.statusList.SetShowHelp(false)
+		return m, fetchStatusesCmd(m.apiToken, m.bulkSpaceID()), true
+	case key.Matches(msg, keys.Assign, keys.Unassign):
+		m.bulkTasks = m.targetTasks()
+		if len(m.bulkTasks) == 0 {
+			return m, nil, true
+		}
+		m.bulkUnassign = key.Matches(msg, keys.Unassign)
+		m.selectedAssignees = make(map[int]struct{})
+		m.state = bulkAssigneeView
+		m.assigneeList = newList([]list.Item{}, assigneeDelegate{selected: m.selectedAssignees}, m.width-h, m.height-v)
+		if m.bulkUnassign {
+			m.assigneeList.Title = fmt.Sprintf("Unassign from %d task(s) (space to select, enter to confirm)", len(m.bulkTasks))
+		} else {
+			m.assigneeList.Title = fmt.Sprintf("Assign to %d task(s) (space to select, enter to confirm)", len(m.bulkTasks))
+		}
+		return m, fetchTeamsCmd(m.apiToken), true
+	case key.Matches(msg, keys.Priority):
+		m.bulkTasks = m.targetTasks()
+		if len(m.bulkTasks) == 0 {
+			return m, nil, true
+		}
+		m.state = bulkPriorityView
+		m.priorityList = newList(priorities, priorityDelegate{}, m.width-h, m.height-v)
+		m.priorityList.Title = fmt.Sprintf("Set priority of %d task(s)", len(m.bulkTasks))
+		return m, nil, true
+	case key.Matches(msg, keys.Tag, keys.Untag):
+		m.bulkTasks = m.targetTasks()
+		if len(m.bulkTasks) == 0 {
+			return m, nil, true
+		}
+		m.state = bulkTagView
+		m.bulkOp = bulkOp{kind: bulkAddTag}
+		if key.Matches(msg, keys.Untag) {
+			m.bulkOp.kind = bulkRemoveTag
+		}
+		m.tagInput = textinput.New()
+		m.tagInput.Placeholder = "tag name"
+		m.tagInput.Focus()
+		return m, textinput.Blink, true
+	case key.Matches(msg, keys.Move):
+		m.bulkTasks = m.targetTasks()
+		if len(m.bulkTasks) == 0 {
+			return m, nil, true
+		}
+		m.movingTasks = true
+		m, cmd := m.startListSelection(m.bulkSpaceID(), fmt.Sprintf("Move %d task(s) to", len(m.bulkTasks)))
+		return m, cmd, true
+	case key.Matches(msg, keys.Delete):
+		if !m.hasMarks() {
+			return m, nil, false
+		}
+		m.bulkTasks = m.targetTasks()
+		m.state = bulkDeleteConfirmationView
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// startBulk switches to the progress view and applies op to the first task.
+// The remaining tasks are processed one by one as results come in.
+func (m model) startBulk(op bulkOp) (tea.Model, tea.Cmd) {
+	m.bulkOp = op
+	m.bulkResults = nil
+	m.state = bulkProgressView
+	m.progress = progress.New(progress.WithDefaultGradient())
+	if len(m.bulkTasks) == 0 {
+		m.state = listView
+		return m, nil
+	}
+	return m, bulkStepCmd(m.apiToken, m.teamID, op, m.bulkTasks[0])
+}
+
+// --- UPDATE & VIEW (BULK STATUS) ---
+func updateBulkStatus(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.statusList.SetSize(msg.Width-h, msg.Height-v)
+	case StatusesResponse:
+		items := make([]list.Item, len(msg.Statuses))
+		for i, s := range msg.Statuses {
+			items[i] = s
+		}
+		m.statusList.SetItems(items)
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.state = listView
+			return m, nil
+		case "enter":
+			selected, ok := m.statusList.SelectedItem().(Status)
+			if ok {
+				return m.startBulk(bulkOp{kind: bulkSetStatus, status: selected.Status})
+			}
+		}
+	}
+	m.statusList, cmd = m.statusList.Update(msg)
+	return m, cmd
+}
+
+// --- UPDATE & VIEW (BULK ASSIGNEE) ---
+func updateBulkAssignee(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.assigneeList.SetSize(msg.Width-h, msg.Height-v)
+	case TeamsResponse:
+		var items []list.Item
+		for _, team := range msg.Teams {
+			if team.ID != m.teamID {
+				continue
+			}
+			for _, member := range team.Members {
+				items = append(items, member.User)
+			}
+		}
+		m.assigneeList.SetItems(items)
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.state = listView
+			return m, nil
+		case " ":
+			selected, ok := m.assigneeList.SelectedItem().(Member)
+			if ok {
+				if _, exists := m.selectedAssignees[selected.ID]; exists {
+					delete(m.selectedAssignees, selected.ID)
+				} else {
+					m.selectedAssignees[selected.ID] = struct{}{}
+				}
+				m.assigneeList.SetDelegate(assigneeDelegate{selected: m.selectedAssignees})
+			}
+			return m, nil
+		case "enter":
+			op := bulkOp{kind: bulkAssign}
+			if m.bulkUnassign {
+				op.kind = bulkUnassign
+			}
+			for id := range m.selectedAssignees {
+				op.assignees = append(op.assignees, id)
+			}
+			if len(op.assignees) == 0 {
+				m.state = listView
+				return m, nil
+			}
+			return m.startBulk(op)
+		}
+	}
+	m.assigneeList, cmd = m.assigneeList.Update(msg)
+	return m, cmd
+}
+
+// --- UPDATE & VIEW (BULK PRIORITY) ---
+func updateBulkPriority(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.priorityList.SetSize(msg.Width-h, msg.Height-v)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.state = listView
+			return m, nil
+		case "enter":
+			selected, ok := m.priorityList.SelectedItem().(Priority)
+			if ok {
+				return m.startBulk(bulkOp{kind: bulkSetPriority, priority: selected.Value})
+			}
+		}
+	}
+	m.priorityList, cmd = m.priorityList.Update(msg)
+	return m, cmd
+}
+
+// --- UPDATE & VIEW (BULK TAG) ---
+func updateBulkTag(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.state = listView
+			return m, nil
+		case tea.KeyEnter:
+			tag := strings.TrimSpace(m.tagInput.Value())
+			if tag == "" {
+				return m, nil
+			}
+			op := m.bulkOp
+			op.tag = tag
+			return m.startBulk(op)
+		}
+	}
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewBulkTag() string {
+	action := "add to"
+	if m.bulkOp.kind == bulkRemoveTag {
+		action = "remove from"
+	}
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Tag to %s %d task(s)", action, len(m.bulkTasks))))
+	b.WriteString("\n\n")
+	b.WriteString(m.tagInput.View())
+	b.WriteString(helpStyle.Render("\n\nenter to apply • esc to cancel"))
+	return appStyle.Render(b.String())
+}
+
+// --- UPDATE & VIEW (BULK DELETE CONFIRMATION) ---
+func updateBulkDeleteConfirmation(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "y", "Y":
+			return m.startBulk(bulkOp{kind: bulkDelete})
+		case "n", "N", "esc":
+			m.state = listView
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewBulkDeleteConfirmation() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n\n   Are you sure you want to delete these %d tasks? (y/n)\n\n", len(m.bulkTasks)))
+	for _, task := range m.bulkTasks {
+		b.WriteString("     - " + task.Name + "\n")
+	}
+	return b.String()
+}
+
+// --- UPDATE & VIEW (BULK PROGRESS) ---
+func updateBulkProgress(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case bulkResultMsg:
+		m.bulkResults = append(m.bulkResults, bulkResult(msg))
+		done := len(m.bulkResults)
+		cmds := []tea.Cmd{m.progress.SetPercent(float64(done) / float64(len(m.bulkTasks)))}
+		if done < len(m.bulkTasks) {
+			cmds = append(cmds, bulkStepCmd(m.apiToken, m.teamID, m.bulkOp, m.bulkTasks[done]))
+		}
+		return m, tea.Batch(cmds...)
+	case progress.FrameMsg:
+		progressModel, cmd := m.progress.Update(msg)
+		m.progress = progressModel.(progress.Model)
+		return m, cmd
+	case tea.KeyMsg:
+		if len(m.bulkResults) < len(m.bulkTasks) {
+			return m, nil
+		}
+		m.state = listView
+		m.loading = true
+		m.bulkTasks = nil
+		m.clearMarks()
+		return m, tea.Batch(
+			m.spinner.Tick,
+			fetchTasksCmd(m.apiToken, m.teamID, m.scope),
+		)
+	}
+	return m, nil
+}
+
+func (m model) viewBulkProgress() string {
+	var b strings.Builder
+	done := len(m.bulkResults)
+	b.WriteString(fmt.Sprintf("\n   %s: %d/%d tasks\n\n", m.bulkOp, done, len(m.bulkTasks)))
+	b.WriteString("   " + m.progress.View() + "\n\n")
+	if done < len(m.bulkTasks) {
+		return b.String()
+	}
+
+	var failed []bulkResult
+	for _, r := range m.bulkResults {
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
+	b.WriteString(fmt.Sprintf("   Done: %d succeeded, %d failed.\n", done-len(failed), len(failed)))
+	for _, r := range failed {
+		b.WriteString(failureStyle.Render(fmt.Sprintf("   ✗ %s: %v", r.task.Name, r.err)) + "\n")
+	}
+	b.WriteString(helpStyle.Render("\n   Press any key to return to the list."))
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+func TestTargetTasks(t *testing.T) {
+	task := func(id string, assignees ...string) Task {
+		t := Task{ID: id}
+		for _, a := range assignees {
+			t.Assignees = append(t.Assignees, Member{Username: a})
+		}
+		return t
+	}
+	// # ann, 1, 2, # bob, 2, 3, # Unassigned, 4
+	items := taskItems([]Task{task("1", "ann"), task("2", "ann", "bob"), task("3", "bob"), task("4")}, taskOrder{Group: "assignee"}, time.Now())
+	tests := []struct {
+		name   string
+		cursor int
+		marked []string
+		visual int // anchor of the visual range, or -1
+		want   []string
+	}{
+		{"cursor", 2, nil, -1, []string{"2"}},
+		{"cursor on a header", 3, nil, -1, nil},
+		{"marks in list order", 1, []string{"4", "1"}, -1, []string{"1", "4"}},
+		{"task in two groups", 0, []string{"2"}, -1, []string{"2"}},
+		{"marks win over the cursor", 5, []string{"1"}, -1, []string{"1"}},
+		{"visual range over a header", 5, nil, 2, []string{"2", "3"}},
+		{"visual range upwards", 1, []string{"4"}, 4, []string{"1", "2", "4"}},
+		{"visual range of headers", 3, nil, 3, nil},
+	}
+	for _, tt := range tests {
+		m := model{list: newList(items, newTaskDelegate(nil, false, 0, newTaskStats(), time.Local), 80, 40)}
+		m.list.Select(tt.cursor)
+		m.markedTasks = make(map[string]struct{})
+		for _, id := range tt.marked {
+			m.markedTasks[id] = struct{}{}
+		}
+		if tt.visual >= 0 {
+			m.visualMode, m.visualAnchor = true, tt.visual
+		}
+		var got []string
+		for _, task := range m.targetTasks() {
+			got = append(got, task.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: targetTasks = %q, want %q", tt.name, got, tt.want)
+		}
+		if m.visualMode {
+			t.Errorf("%s: visual range still open after targetTasks", tt.name)
+		}
+	}
+}
+
+func TestBulkProgressCollectsFailures(t *testing.T) {
+	m := model{
+		state:     bulkProgressView,
+		bulkOp:    bulkOp{kind: bulkSetStatus, status: "done"},
+		bulkTasks: []Task{{ID: "1", Name: "First"}, {ID: "2", Name: "Second"}, {ID: "3", Name: "Third"}},
+		progress:  progress.New(),
+	}
+	results := []bulkResultMsg{
+		{task: m.bulkTasks[0]},
+		{task: m.bulkTasks[1], err: errors.New("not found")},
+		{task: m.bulkTasks[2]},
+	}
+	for i, r := range results {
+		updated, _ := updateBulkProgress(r, m)
+		m = updated.(model)
+		if len(m.bulkResults) != i+1 {
+			t.Fatalf("after result %d: %d results", i+1, len(m.bulkResults))
+		}
+		if done := strings.Contains(m.viewBulkProgress(), "Done:"); done != (i == len(results)-1) {
+			t.Errorf("after result %d: done shown = %v", i+1, done)
+		}
+	}
+	view := m.viewBulkProgress()
+	for _, want := range []string{"3/3 tasks", "2 succeeded, 1 failed", "Second: not found"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("progress view has no %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "First") || strings.Contains(view, "Third") {
+		t.Errorf("progress view lists a task that succeeded:\n%s", view)
+	}
+}
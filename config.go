@@ -0,0 +1,771 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// --- CONFIG FILE ---
+
+// config is the content of config.yaml in the configuration directory.
+type config struct {
+	CurrentProfile string               `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*profile  `yaml:"profiles,omitempty"`
+	DefaultSpace   string               `yaml:"default_space,omitempty"`
+	DefaultList    string               `yaml:"default_list,omitempty"`
+	Filter         string               `yaml:"filter,omitempty"`
+	Sort           string               `yaml:"sort,omitempty"`
+	Group          string               `yaml:"group,omitempty"`
+	Spaces         map[string]taskOrder `yaml:"spaces,omitempty"`
+	Views          map[string]savedView `yaml:"views,omitempty"`
+	DefaultView    string               `yaml:"default_view,omitempty"`
+	DateFormat     string               `yaml:"date_format,omitempty"`
+	Theme          string               `yaml:"theme,omitempty"`
+	Themes         map[string]palette   `yaml:"themes,omitempty"`
+	Keys           map[string]keyNames  `yaml:"keys,omitempty"`
+	Credentials    credentialSettings   `yaml:"credentials,omitempty"`
+	OAuth          oauthSettings        `yaml:"oauth,omitempty"`
+	Git            gitSettings          `yaml:"git,omitempty"`
+}
+
+// settings is the configuration of the running command, loaded before any
+// command runs.
+var settings = &config{}
+
+func configPath() string {
+	return filepath.Join(configDir(), "config.yaml")
+}
+
+// readConfig loads config.yaml, returning an empty config when it does not
+// exist yet.
+func readConfig() (*config, error) {
+	cfg := &config{}
+	data, err := os.ReadFile(configPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath(), err)
+	}
+	for name, p := range cfg.Profiles {
+		p.Name = name
+	}
+	return cfg, nil
+}
+
+// save writes the config back. Only the entries that changed since the file
+// was read are replaced, so comments and the order of keys are kept. The file
+// holds API tokens, so it is only readable by the user.
+func (c *config) save() error {
+	root, err := readConfigNode()
+	if err != nil {
+		return err
+	}
+	old, err := readConfig()
+	if err != nil {
+		return err
+	}
+	var was, now yaml.Node
+	if err := was.Encode(old); err != nil {
+		return err
+	}
+	if err := now.Encode(c); err != nil {
+		return err
+	}
+	mergeNode(root, &was, &now)
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	return writeConfigFile(data)
+}
+
+// mergeNode makes dst, a node of the config file, hold src. was is what the
+// file held when it was read, so entries that did not change are left as
+// they are written in the file, and entries the config doesn't know are
+// kept.
+func mergeNode(dst, was, src *yaml.Node) {
+	if was != nil && sameNode(was, src) {
+		return
+	}
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+		*dst = *src
+		dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+		return
+	}
+	var content []*yaml.Node
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		path := []string{dst.Content[i].Value}
+		value := lookupNode(src, path)
+		if value == nil {
+			if lookupNode(was, path) == nil {
+				content = append(content, dst.Content[i], dst.Content[i+1])
+			}
+			continue
+		}
+		mergeNode(dst.Content[i+1], lookupNode(was, path), value)
+		content = append(content, dst.Content[i], dst.Content[i+1])
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if lookupNode(dst, []string{src.Content[i].Value}) == nil {
+			content = append(content, src.Content[i], src.Content[i+1])
+		}
+	}
+	dst.Content = content
+}
+
+func sameNode(a, b *yaml.Node) bool {
+	x, err := yaml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := yaml.Marshal(b)
+	return err == nil && bytes.Equal(x, y)
+}
+
+func writeConfigFile(data []byte) error {
+	if err := os.MkdirAll(configDir(), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath(), data, 0600)
+}
+
+func (c *config) validate() error {
+	if err := (taskOrder{Sort: c.Sort, Group: c.Group}).validate(); err != nil {
+		return err
+	}
+	for spaceID, order := range c.Spaces {
+		if err := order.validate(); err != nil {
+			return fmt.Errorf("spaces.%s: %w", spaceID, err)
+		}
+	}
+	for name, view := range c.Views {
+		if err := view.order().validate(); err != nil {
+			return fmt.Errorf("views.%s: %w", name, err)
+		}
+	}
+	if _, ok := c.Views[c.DefaultView]; c.DefaultView != "" && !ok {
+		return fmt.Errorf("default_view %q is not one of the views", c.DefaultView)
+	}
+	if _, err := c.palette(); err != nil {
+		return err
+	}
+	if _, err := newCredentialStore(c.Credentials, false); err != nil {
+		return err
+	}
+	if err := c.Git.validate(); err != nil {
+		return err
+	}
+	return c.checkKeys()
+}
+
+// keyContexts are the sets of key bindings that are active at the same time,
+// in the task list and in the space tree. Bindings of different contexts may
+// share a key, like tag and list_template.
+var keyContexts = [][]string{
+	{
+		"view", "edit", "delete", "duplicate", "switch_workspace", "mark", "visual",
+		"status", "assign", "unassign", "priority", "move", "tag", "untag", "goto",
+		"sort", "reverse_sort", "group", "save_view", "tag_filter", "agenda", "calendar",
+	},
+	{
+		"expand", "collapse", "new_list", "new_folder", "list_template", "rename",
+		"archive", "delete", "switch_workspace",
+	},
+}
+
+// checkKeys reports unknown bindings and keys that are bound to two actions
+// of the same context.
+func (c *config) checkKeys() error {
+	km := defaultKeyMap()
+	bindings := km.bindings()
+	for action, names := range c.Keys {
+		binding, ok := bindings[action]
+		if !ok {
+			return fmt.Errorf("unknown key binding %q", action)
+		}
+		binding.SetKeys(names...)
+	}
+	for _, actions := range keyContexts {
+		bound := make(map[string]string)
+		for _, action := range actions {
+			for _, name := range bindings[action].Keys() {
+				if other, ok := bound[name]; ok && other != action {
+					return fmt.Errorf("keys: %q is bound to both %s and %s", keyLabel(name), other, action)
+				}
+				bound[name] = action
+			}
+		}
+	}
+	return nil
+}
+
+// loadSettings reads the config file and applies its theme and key bindings.
+func loadSettings() error {
+	cfg, err := readConfig()
+	if err != nil {
+		return err
+	}
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("%s: %w", configPath(), err)
+	}
+	p, _ := cfg.palette()
+	applyTheme(p)
+	keys = defaultKeyMap()
+	for action, names := range cfg.Keys {
+		binding := keys.bindings()[action]
+		binding.SetKeys(names...)
+		binding.SetHelp(keyLabel(names[0]), binding.Help().Desc)
+	}
+	settings = cfg
+	return nil
+}
+
+// formatDate formats t with the configured date format.
+func formatDate(t time.Time) string {
+	if settings.DateFormat != "" {
+		return t.Format(settings.DateFormat)
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// --- THEME ---
+
+// palette is a set of colors feeding the styles of the TUI. Colors are ANSI
+// numbers or hex codes; empty entries fall back to the dark theme.
+type palette struct {
+	Accent     string `yaml:"accent"`
+	AccentText string `yaml:"accent_text"`
+	Highlight  string `yaml:"highlight"`
+	Muted      string `yaml:"muted"`
+	Success    string `yaml:"success"`
+	Error      string `yaml:"error"`
+}
+
+var builtinThemes = map[string]palette{
+	"dark": {
+		Accent:     "#25A065",
+		AccentText: "#FFFDF5",
+		Highlight:  "205",
+		Muted:      "240",
+		Success:    "#04B575",
+		Error:      "#ff5f5f",
+	},
+	"light": {
+		Accent:     "#1E7F4F",
+		AccentText: "#FFFFFF",
+		Highlight:  "#C2187A",
+		Muted:      "#6C6C6C",
+		Success:    "#027A48",
+		Error:      "#C62828",
+	},
+}
+
+// theme is the palette the styles were last built from.
+var theme = builtinThemes["dark"]
+
+func (c *config) palette() (palette, error) {
+	name := c.Theme
+	if name == "" {
+		name = "dark"
+	}
+	p, ok := c.Themes[name]
+	if !ok {
+		p, ok = builtinThemes[name]
+	}
+	if !ok {
+		return palette{}, fmt.Errorf("unknown theme %q", name)
+	}
+	base := builtinThemes["dark"]
+	for _, field := range []struct{ value, fallback *string }{
+		{&p.Accent, &base.Accent},
+		{&p.AccentText, &base.AccentText},
+		{&p.Highlight, &base.Highlight},
+		{&p.Muted, &base.Muted},
+		{&p.Success, &base.Success},
+		{&p.Error, &base.Error},
+	} {
+		if *field.value == "" {
+			*field.value = *field.fallback
+		}
+	}
+	return p, nil
+}
+
+func applyTheme(p palette) {
+	theme = p
+	titleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.AccentText)).Background(lipgloss.Color(p.Accent)).Padding(0, 1)
+	statusMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Success)).Render
+	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Highlight))
+	blurredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Muted))
+	cursorStyle = focusedStyle
+	helpStyle = blurredStyle
+	failureStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Error))
+}
+
+// newList creates a list whose title follows the theme.
+func newList(items []list.Item, delegate list.ItemDelegate, width, height int) list.Model {
+	l := list.New(items, delegate, width, height)
+	l.Styles.Title = titleStyle
+	return l
+}
+
+// newItemDelegate is the default list delegate with the selection drawn in
+// the theme's highlight color.
+func newItemDelegate() list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+	c := lipgloss.Color(theme.Highlight)
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.Foreground(c).BorderLeftForeground(c)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.Foreground(c).BorderLeftForeground(c)
+	return d
+}
+
+// --- KEY BINDINGS ---
+
+// keyNames is a list of keys in the config file. A single key or a comma
+// separated string is accepted as well as a YAML list.
+type keyNames []string
+
+func (k *keyNames) UnmarshalYAML(node *yaml.Node) error {
+	var names []string
+	if node.Kind == yaml.ScalarNode {
+		names = splitValues(node.Value)
+	} else if err := node.Decode(&names); err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("line %d: no keys given", node.Line)
+	}
+	for i, name := range names {
+		if name == "space" {
+			names[i] = " "
+		}
+	}
+	*k = names
+	return nil
+}
+
+func keyLabel(name string) string {
+	if name == " " {
+		return "space"
+	}
+	return name
+}
+
+// keyMap holds the remappable keys of the task and space lists.
+type keyMap struct {
+	View            key.Binding
+	Edit            key.Binding
+	Delete          key.Binding
+	Duplicate       key.Binding
+	SwitchWorkspace key.Binding
+	Mark            key.Binding
+	Visual          key.Binding
+	Status          key.Binding
+	Assign          key.Binding
+	Unassign        key.Binding
+	Priority        key.Binding
+	Move            key.Binding
+	Tag             key.Binding
+	Untag           key.Binding
+	GoTo            key.Binding
+	Expand          key.Binding
+	Collapse        key.Binding
+	NewList         key.Binding
+	NewFolder       key.Binding
+	ListTemplate    key.Binding
+	Rename          key.Binding
+	Archive         key.Binding
+	Sort            key.Binding
+	ReverseSort     key.Binding
+	Group           key.Binding
+	SaveView        key.Binding
+	TagFilter       key.Binding
+	Agenda          key.Binding
+	Calendar        key.Binding
+}
+
+var keys = defaultKeyMap()
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		View:            key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "view")),
+		Edit:            key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+		Delete:          key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+		Duplicate:       key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "duplicate")),
+		SwitchWorkspace: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "switch workspace")),
+		Mark:            key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark")),
+		Visual:          key.NewBinding(key.WithKeys("V"), key.WithHelp("V", "mark range")),
+		Status:          key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "set status")),
+		Assign:          key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "assign")),
+		Unassign:        key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "unassign")),
+		Priority:        key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "set priority")),
+		Move:            key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "move to list")),
+		Tag:             key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "add tag")),
+		Untag:           key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "remove tag")),
+		GoTo:            key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "go to task")),
+		Expand:          key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "expand")),
+		Collapse:        key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "collapse")),
+		NewList:         key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new list")),
+		NewFolder:       key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "new folder")),
+		ListTemplate:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "new list from template")),
+		Rename:          key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rename")),
+		Archive:         key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "archive")),
+		Sort:            key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "sort")),
+		ReverseSort:     key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "reverse sort")),
+		Group:           key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "group")),
+		SaveView:        key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "save view")),
+		TagFilter:       key.NewBinding(key.WithKeys("#"), key.WithHelp("#", "filter by tag")),
+		Agenda:          key.NewBinding(key.WithKeys("@"), key.WithHelp("@", "agenda")),
+		Calendar:        key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "calendar")),
+	}
+}
+
+// bindings maps the names used in the config file to the bindings of k.
+func (k *keyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"view":             &k.View,
+		"edit":             &k.Edit,
+		"delete":           &k.Delete,
+		"duplicate":        &k.Duplicate,
+		"switch_workspace": &k.SwitchWorkspace,
+		"mark":             &k.Mark,
+		"visual":           &k.Visual,
+		"status":           &k.Status,
+		"assign":           &k.Assign,
+		"unassign":         &k.Unassign,
+		"priority":         &k.Priority,
+		"move":             &k.Move,
+		"tag":              &k.Tag,
+		"untag":            &k.Untag,
+		"goto":             &k.GoTo,
+		"expand":           &k.Expand,
+		"collapse":         &k.Collapse,
+		"new_list":         &k.NewList,
+		"new_folder":       &k.NewFolder,
+		"list_template":    &k.ListTemplate,
+		"rename":           &k.Rename,
+		"archive":          &k.Archive,
+		"sort":             &k.Sort,
+		"reverse_sort":     &k.ReverseSort,
+		"group":            &k.Group,
+		"save_view":        &k.SaveView,
+		"tag_filter":       &k.TagFilter,
+		"agenda":           &k.Agenda,
+		"calendar":         &k.Calendar,
+	}
+}
+
+// --- SORTING ---
+
+// msTime converts a ClickUp millisecond timestamp.
+func msTime(ms string) (time.Time, bool) {
+	n, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil || ms == "" {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(n), true
+}
+
+// taskSorters compare two tasks for each sort order. Tasks without a value
+// sort last.
+var taskSorters = map[string]func(a, b Task) bool{
+	"priority": func(a, b Task) bool {
+		pa, pb := taskPriorityValue(a), taskPriorityValue(b)
+		if pa == 0 || pb == 0 {
+			return pb == 0 && pa != 0
+		}
+		return pa < pb
+	},
+	"due": func(a, b Task) bool {
+		da, okA := msTime(a.DueDate)
+		db, okB := msTime(b.DueDate)
+		if !okA || !okB {
+			return okA && !okB
+		}
+		return da.Before(db)
+	},
+	"created": func(a, b Task) bool {
+		ca, _ := msTime(a.DateCreated)
+		cb, _ := msTime(b.DateCreated)
+		return ca.After(cb)
+	},
+	"updated": func(a, b Task) bool {
+		ua, _ := msTime(a.DateUpdated)
+		ub, _ := msTime(b.DateUpdated)
+		return ua.After(ub)
+	},
+	"status": func(a, b Task) bool {
+		return a.Status.Order < b.Status.Order
+	},
+	"name": func(a, b Task) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	},
+}
+
+func sortKeys() []string {
+	var names []string
+	for name := range taskSorters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortTasks orders tasks by order, one of the taskSorters keys; a leading "-"
+// reverses it. An empty order or "none" keeps the API order.
+func sortTasks(tasks []Task, order string) {
+	less, ok := taskSorters[strings.TrimPrefix(order, "-")]
+	if !ok {
+		return
+	}
+	if strings.HasPrefix(order, "-") {
+		sort.SliceStable(tasks, func(i, j int) bool { return less(tasks[j], tasks[i]) })
+		return
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return less(tasks[i], tasks[j]) })
+}
+
+// --- CONFIG (CLI) ---
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change the configuration file",
+	Long: `Read and change ` + "`config.yaml`" + ` in the configuration directory.
+
+Keys are dotted paths into the file, for example:
+  default_space, default_list, filter, sort, group, spaces.<id>.sort,
+  spaces.<id>.group, views.<name>.filter, default_view, date_format, theme,
+  themes.<name>.accent, keys.view, profiles.<name>.team_id,
+  credentials.store, credentials.command, credentials.file`,
+	// The config file is not loaded for these commands, so that a broken
+	// file can still be fixed.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the configuration file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(configPath())
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a configuration value, or the whole file",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		root, err := readConfigNode()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		node := root
+		if len(args) == 1 {
+			node = lookupNode(root, strings.Split(args[0], "."))
+			if node == nil {
+				fmt.Printf("%s is not set.\n", args[0])
+				os.Exit(1)
+			}
+		}
+		if node.Kind == yaml.ScalarNode {
+			fmt.Println(node.Value)
+			return
+		}
+		out, err := yaml.Marshal(node)
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(out))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:     "set <key> <value>",
+	Short:   "Change a configuration value",
+	Example: "  clup config set theme light\n  clup config set keys.view '[v, enter]'",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		root, err := readConfigNode()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		var value yaml.Node
+		if err := yaml.Unmarshal([]byte(args[1]), &value); err != nil || len(value.Content) == 0 {
+			value = yaml.Node{Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: args[1]}}}
+		}
+		if err := setNode(root, strings.Split(args[0], "."), value.Content[0]); err != nil {
+			fmt.Println("Error changing config:", err)
+			os.Exit(1)
+		}
+		data, err := yaml.Marshal(root)
+		if err != nil {
+			fmt.Println("Error changing config:", err)
+			os.Exit(1)
+		}
+		if err := checkConfig(data); err != nil {
+			fmt.Println("Error changing config:", err)
+			os.Exit(1)
+		}
+		if err := writeConfigFile(data); err != nil {
+			fmt.Println("Error saving config:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in $EDITOR",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := os.Stat(configPath()); errors.Is(err, fs.ErrNotExist) {
+			if err := writeConfigFile(nil); err != nil {
+				fmt.Println("Error creating config:", err)
+				os.Exit(1)
+			}
+		}
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			editor = "vi"
+		}
+		fields := strings.Fields(editor)
+		editCmd := exec.Command(fields[0], append(fields[1:], configPath())...)
+		editCmd.Stdin, editCmd.Stdout, editCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := editCmd.Run(); err != nil {
+			fmt.Println("Error running editor:", err)
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(configPath())
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		if err := checkConfig(data); err != nil {
+			fmt.Println("Warning: the config is invalid:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// checkConfig reports whether data is a valid config file.
+func checkConfig(data []byte) error {
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	return cfg.validate()
+}
+
+// readConfigNode loads the config file as a YAML mapping node, which keeps
+// comments and key order when the file is changed.
+func readConfigNode() (*yaml.Node, error) {
+	data, err := os.ReadFile(configPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || len(bytes.TrimSpace(data)) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", configPath())
+	}
+	return doc.Content[0], nil
+}
+
+func lookupNode(node *yaml.Node, path []string) *yaml.Node {
+	for _, name := range path {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				next = node.Content[i+1]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// setNode stores value at path, creating the mappings on the way.
+func setNode(node *yaml.Node, path []string, value *yaml.Node) error {
+	for i, name := range path {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", strings.Join(path[:i], "."))
+		}
+		var next *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == name {
+				next = node.Content[j+1]
+				if i == len(path)-1 {
+					node.Content[j+1] = value
+					return nil
+				}
+			}
+		}
+		if next == nil {
+			next = value
+			if i < len(path)-1 {
+				next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, next)
+		}
+		node = next
+	}
+	return nil
+}
+
+// deleteNode removes the entry at path, reporting whether there was one.
+func deleteNode(node *yaml.Node, path []string) bool {
+	parent := lookupNode(node, path[:len(path)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return false
+	}
+	name := path[len(path)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == name {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	configCmd.AddCommand(configPathCmd, configGetCmd, configSetCmd, configEditCmd)
+}
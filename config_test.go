@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckKeys(t *testing.T) {
+	tests := []struct {
+		keys    map[string]keyNames
+		wantErr string
+	}{
+		{keys: nil},
+		{keys: map[string]keyNames{"view": {"x"}, "edit": {"E", "e"}}},
+		{keys: map[string]keyNames{"tag": {"n"}}},
+		{keys: map[string]keyNames{"mark": {"x", "x"}}},
+		{keys: map[string]keyNames{"view": {"e"}}, wantErr: `"e" is bound to both view and edit`},
+		{keys: map[string]keyNames{"rename": {"left"}}, wantErr: `"left" is bound to both collapse and rename`},
+		{keys: map[string]keyNames{"tag": {" "}}, wantErr: `"space" is bound to both mark and tag`},
+		{keys: map[string]keyNames{"colour": {"c"}}, wantErr: "unknown key binding"},
+	}
+	for _, tt := range tests {
+		err := (&config{Keys: tt.keys}).checkKeys()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("checkKeys(%v): %v", tt.keys, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("checkKeys(%v) = %v, want an error with %q", tt.keys, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSaveKeepsComments(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	original := `# clup settings
+current_profile: work # the default
+profiles:
+  # my job
+  work:
+    api_token: pk_1
+    team_id: "1"
+  home:
+    api_token: pk_2
+    team_id: "2"
+keys:
+  mark: space # easier to reach
+unknown: kept
+`
+	if err := writeConfigFile([]byte(original)); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := readConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.CurrentProfile = "home"
+	delete(cfg.Profiles, "work")
+	cfg.Profiles["new"] = &profile{APIToken: "pk_3", TeamID: "3"}
+	if err := cfg.save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(configPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"# clup settings\n",
+		"current_profile: home # the default\n",
+		"mark: space # easier to reach\n",
+		"unknown: kept\n",
+		"new:\n",
+		"api_token: pk_3\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("saved config has no %q:\n%s", want, got)
+		}
+	}
+	for _, gone := range []string{"work:", "pk_1", "# my job"} {
+		if strings.Contains(got, gone) {
+			t.Errorf("saved config still has %q:\n%s", gone, got)
+		}
+	}
+}
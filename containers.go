@@ -0,0 +1,621 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// --- LISTS AND FOLDERS ---
+
+// Lists and folders are created, renamed, archived and deleted from the
+// space tree. New lists can start from one of the workspace's list templates.
+
+type containerOpKind int
+
+const (
+	createListOp containerOpKind = iota
+	createFolderOp
+	renameOp
+	archiveOp
+	deleteOp
+)
+
+// containerOp is a change to the list or folder selected in the tree.
+type containerOp struct {
+	kind     containerOpKind
+	node     treeNode
+	template *listTemplate
+}
+
+type listTemplate struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (t listTemplate) FilterValue() string { return t.Name }
+func (t listTemplate) Title() string       { return t.Name }
+func (t listTemplate) Description() string { return t.ID }
+
+type listTemplatesResponse struct {
+	Templates []listTemplate `json:"templates"`
+}
+
+// containerDoneMsg reports a finished change in a space.
+type containerDoneMsg struct {
+	spaceID string
+	status  string
+}
+
+// containerURL is the API URL of a list or folder.
+func containerURL(scope taskScope) string {
+	return fmt.Sprintf("https://api.clickup.com/api/v2/%s/%s", scope.kind, scope.id)
+}
+
+// sendContainerRequest sends a request about lists or folders and returns the
+// response body.
+func sendContainerRequest(apiToken, method, url string, payload interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewBuffer(data)
+	}
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", apiToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", string(body))
+	}
+	return body, nil
+}
+
+// createList creates a list in a space or folder, from a template if one is
+// given.
+func createList(apiToken string, parent taskScope, name string, template *listTemplate) error {
+	if template != nil {
+		url := fmt.Sprintf("%s/list_template/%s", containerURL(parent), template.ID)
+		_, err := sendContainerRequest(apiToken, "POST", url, map[string]interface{}{"name": name})
+		if err != nil {
+			return fmt.Errorf("create list from template failed: %v", err)
+		}
+		return nil
+	}
+	_, err := sendContainerRequest(apiToken, "POST", containerURL(parent)+"/list", map[string]string{"name": name})
+	if err != nil {
+		return fmt.Errorf("create list failed: %v", err)
+	}
+	return nil
+}
+
+func createFolder(apiToken, spaceID, name string) error {
+	url := fmt.Sprintf("https://api.clickup.com/api/v2/space/%s/folder", spaceID)
+	if _, err := sendContainerRequest(apiToken, "POST", url, map[string]string{"name": name}); err != nil {
+		return fmt.Errorf("create folder failed: %v", err)
+	}
+	return nil
+}
+
+func renameContainer(apiToken string, scope taskScope, name string) error {
+	if _, err := sendContainerRequest(apiToken, "PUT", containerURL(scope), map[string]string{"name": name}); err != nil {
+		return fmt.Errorf("rename %s failed: %v", scope.kind, err)
+	}
+	return nil
+}
+
+// archiveContainer archives a list or folder. Archived lists and folders are
+// hidden from clup and can be restored in ClickUp. The API documents no way
+// to archive them, so the list or folder is loaded again to check that the
+// update did archive it.
+func archiveContainer(apiToken string, scope taskScope) error {
+	if _, err := sendContainerRequest(apiToken, "PUT", containerURL(scope), map[string]bool{"archived": true}); err != nil {
+		return fmt.Errorf("archive %s failed: %v", scope.kind, err)
+	}
+	body, err := sendContainerRequest(apiToken, "GET", containerURL(scope), nil)
+	if err != nil {
+		return fmt.Errorf("archive %s failed: %v", scope.kind, err)
+	}
+	var container struct {
+		Archived bool `json:"archived"`
+	}
+	if err := json.Unmarshal(body, &container); err != nil {
+		return err
+	}
+	if !container.Archived {
+		return fmt.Errorf("archive %s failed: ClickUp did not archive %s, archive it in ClickUp instead", scope.kind, scope.name)
+	}
+	return nil
+}
+
+func deleteContainer(apiToken string, scope taskScope) error {
+	if _, err := sendContainerRequest(apiToken, "DELETE", containerURL(scope), nil); err != nil {
+		return fmt.Errorf("delete %s failed: %v", scope.kind, err)
+	}
+	return nil
+}
+
+func fetchListTemplatesCmd(apiToken, teamID string) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/team/%s/list_template", teamID)
+		body, err := sendContainerRequest(apiToken, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("fetch list templates failed: %v", err)
+		}
+		var templates listTemplatesResponse
+		if err := json.Unmarshal(body, &templates); err != nil {
+			return err
+		}
+		return templates
+	}
+}
+
+// runContainerOpCmd applies op, with name for new and renamed lists and
+// folders.
+func runContainerOpCmd(apiToken string, op containerOp, name string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		var status string
+		scope := op.node.scope
+		switch op.kind {
+		case createListOp:
+			err = createList(apiToken, op.node.container(), name, op.template)
+			status = "Created list " + name
+		case createFolderOp:
+			err = createFolder(apiToken, op.node.spaceID, name)
+			status = "Created folder " + name
+		case renameOp:
+			err = renameContainer(apiToken, scope, name)
+			status = fmt.Sprintf("Renamed %s %s to %s", scope.kind, scope.name, name)
+		case archiveOp:
+			err = archiveContainer(apiToken, scope)
+			status = fmt.Sprintf("Archived %s %s", scope.kind, scope.name)
+		case deleteOp:
+			err = deleteContainer(apiToken, scope)
+			status = fmt.Sprintf("Deleted %s %s", scope.kind, scope.name)
+		}
+		if err != nil {
+			return err
+		}
+		return containerDoneMsg{spaceID: op.node.spaceID, status: status}
+	}
+}
+
+// --- UPDATE & VIEW (LISTS AND FOLDERS) ---
+
+// updateTreeActions handles the keys that change lists and folders in the
+// space tree.
+func updateTreeActions(msg tea.KeyMsg, m model, n treeNode) (tea.Model, tea.Cmd, bool) {
+	changeable := n.scope.kind != scopeSpace
+	switch {
+	case key.Matches(msg, keys.NewList):
+		m, cmd := m.startContainerName(containerOp{kind: createListOp, node: n})
+		return m, cmd, true
+	case key.Matches(msg, keys.NewFolder):
+		m, cmd := m.startContainerName(containerOp{kind: createFolderOp, node: n})
+		return m, cmd, true
+	case key.Matches(msg, keys.ListTemplate):
+		m.containerOp = containerOp{kind: createListOp, node: n}
+		m.state = listTemplateView
+		h, v := appStyle.GetFrameSize()
+		m.listTemplates = newList([]list.Item{}, newItemDelegate(), m.width-h, m.height-v)
+		m.listTemplates.Title = "New list from template in " + n.container().name
+		return m, fetchListTemplatesCmd(m.apiToken, m.teamID), true
+	case key.Matches(msg, keys.Rename) && changeable:
+		m, cmd := m.startContainerName(containerOp{kind: renameOp, node: n})
+		return m, cmd, true
+	case key.Matches(msg, keys.Archive) && changeable:
+		m.containerOp = containerOp{kind: archiveOp, node: n}
+		m.state = containerConfirmationView
+		return m, nil, true
+	case key.Matches(msg, keys.Delete) && changeable:
+		m.containerOp = containerOp{kind: deleteOp, node: n}
+		m.state = containerConfirmationView
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// applyContainerOp runs the pending change and returns to the tree, where the
+// space shows as loading until it has been reloaded.
+func (m model) applyContainerOp(name string) (tea.Model, tea.Cmd) {
+	op := m.containerOp
+	m.state = spaceSelectionView
+	m.tree.loading[op.node.spaceID] = true
+	m.tree.expanded[nodeKey(scopeSpace, op.node.spaceID)] = true
+	if op.kind == createListOp {
+		parent := op.node.container()
+		m.tree.expanded[nodeKey(parent.kind, parent.id)] = true
+	}
+	m.setTreeItems()
+	return m, runContainerOpCmd(m.apiToken, op, name)
+}
+
+func (m model) startContainerName(op containerOp) (model, tea.Cmd) {
+	m.containerOp = op
+	m.state = containerNameView
+	m.nameInput = textinput.New()
+	m.nameInput.Width = 50
+	switch op.kind {
+	case createListOp:
+		m.nameInput.Placeholder = "list name"
+		if op.template != nil {
+			m.nameInput.SetValue(op.template.Name)
+		}
+	case createFolderOp:
+		m.nameInput.Placeholder = "folder name"
+	case renameOp:
+		m.nameInput.SetValue(op.node.scope.name)
+	}
+	m.nameInput.Focus()
+	return m, textinput.Blink
+}
+
+func (m model) containerTitle() string {
+	op := m.containerOp
+	switch op.kind {
+	case createListOp:
+		if op.template != nil {
+			return fmt.Sprintf("New list from %s in %s", op.template.Name, op.node.container().name)
+		}
+		return "New list in " + op.node.container().name
+	case createFolderOp:
+		return "New folder in " + m.tree.spaceName(op.node.spaceID)
+	}
+	return fmt.Sprintf("Rename %s %s", op.node.scope.kind, op.node.scope.name)
+}
+
+func updateContainerName(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.state = spaceSelectionView
+			return m, nil
+		case tea.KeyEnter:
+			name := strings.TrimSpace(m.nameInput.Value())
+			if name == "" || (m.containerOp.kind == renameOp && name == m.containerOp.node.scope.name) {
+				return m, nil
+			}
+			return m.applyContainerOp(name)
+		}
+	}
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewContainerName() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.containerTitle()))
+	b.WriteString("\n\n")
+	b.WriteString(m.nameInput.View())
+	b.WriteString(helpStyle.Render("\n\nenter to save • esc to cancel"))
+	return appStyle.Render(b.String())
+}
+
+func updateListTemplate(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.listTemplates.SetSize(msg.Width-h, msg.Height-v)
+	case listTemplatesResponse:
+		items := make([]list.Item, len(msg.Templates))
+		for i, t := range msg.Templates {
+			items[i] = t
+		}
+		return m, m.listTemplates.SetItems(items)
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if m.listTemplates.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "esc":
+			m.state = spaceSelectionView
+			return m, nil
+		case "enter":
+			if t, ok := m.listTemplates.SelectedItem().(listTemplate); ok {
+				op := m.containerOp
+				op.template = &t
+				return m.startContainerName(op)
+			}
+		}
+	}
+	m.listTemplates, cmd = m.listTemplates.Update(msg)
+	return m, cmd
+}
+
+func updateContainerConfirmation(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "y", "Y":
+			return m.applyContainerOp("")
+		case "n", "N", "esc":
+			m.state = spaceSelectionView
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewContainerConfirmation() string {
+	return "\n\n   " + confirmationQuestion(m.containerOp.kind, m.containerOp.node.scope) + " (y/n)\n\n"
+}
+
+// confirmationQuestion asks before archiving or deleting a list or folder.
+func confirmationQuestion(kind containerOpKind, scope taskScope) string {
+	verb := "delete"
+	if kind == archiveOp {
+		verb = "archive"
+	}
+	contents := "its tasks"
+	if scope.kind == scopeFolder {
+		contents = "all its lists and tasks"
+	}
+	return fmt.Sprintf("Are you sure you want to %s the %s '%s' with %s?", verb, scope.kind, scope.name, contents)
+}
+
+// --- LISTS AND FOLDERS (CLI) ---
+
+var (
+	containerTemplate string
+	containerYes      bool
+)
+
+var listsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a list",
+	Long: `Create a list in the folder given with --folder, or directly in the space.
+With --template, the list is created from one of the workspace's list templates
+(see clup lists templates).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		space := singleSpace(apiToken, teamID)
+		parent := space.scope
+		if hierarchyFolder != "" {
+			parent = findContainer(apiToken, []treeNode{space}, scopeFolder, hierarchyFolder).scope
+		}
+		var template *listTemplate
+		if containerTemplate != "" {
+			t := findListTemplate(apiToken, teamID, containerTemplate)
+			template = &t
+		}
+		if err := createList(apiToken, parent, args[0], template); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created list %s in %s.\n", args[0], parent.name)
+	},
+}
+
+var listsTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the workspace's list templates",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME")
+		for _, t := range fetchListTemplates(apiToken, teamID) {
+			fmt.Fprintf(w, "%s\t%s\n", t.ID, t.Name)
+		}
+		w.Flush()
+	},
+}
+
+var foldersCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a folder",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		space := singleSpace(apiToken, teamID)
+		if err := createFolder(apiToken, space.spaceID, args[0]); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created folder %s in %s.\n", args[0], space.scope.name)
+	},
+}
+
+// containerCommands returns the rename, archive and delete commands for
+// lists or folders, which are given by name or ID.
+func containerCommands(kind scopeKind) []*cobra.Command {
+	rename := &cobra.Command{
+		Use:   fmt.Sprintf("rename <%s> <new-name>", kind),
+		Short: fmt.Sprintf("Rename a %s", kind),
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			apiToken, teamID := requireCredentials()
+			n := findContainer(apiToken, spaceNodes(apiToken, teamID), kind, args[0])
+			if err := renameContainer(apiToken, n.scope, args[1]); err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Renamed %s %s to %s.\n", kind, n.scope.name, args[1])
+		},
+	}
+	var commands = []*cobra.Command{rename}
+	for _, op := range []containerOpKind{archiveOp, deleteOp} {
+		op := op
+		verb, done := "delete", "Deleted"
+		if op == archiveOp {
+			verb, done = "archive", "Archived"
+		}
+		commands = append(commands, &cobra.Command{
+			Use:   fmt.Sprintf("%s <%s>", verb, kind),
+			Short: fmt.Sprintf("%s a %s", strings.ToUpper(verb[:1])+verb[1:], kind),
+			Args:  cobra.ExactArgs(1),
+			Run: func(cmd *cobra.Command, args []string) {
+				apiToken, teamID := requireCredentials()
+				n := findContainer(apiToken, spaceNodes(apiToken, teamID), kind, args[0])
+				if !containerYes && !confirm(confirmationQuestion(op, n.scope)) {
+					return
+				}
+				var err error
+				if op == archiveOp {
+					err = archiveContainer(apiToken, n.scope)
+				} else {
+					err = deleteContainer(apiToken, n.scope)
+				}
+				if err != nil {
+					fmt.Println("Error:", err)
+					os.Exit(1)
+				}
+				fmt.Printf("%s %s %s.\n", done, kind, n.scope.name)
+			},
+		})
+		commands[len(commands)-1].Flags().BoolVarP(&containerYes, "yes", "y", false, "don't ask for confirmation")
+	}
+	return commands
+}
+
+// confirm asks a yes or no question on the terminal.
+func confirm(question string) bool {
+	fmt.Print(question + " (y/n) ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// spaceNodes returns the spaces selected with --space as tree nodes.
+func spaceNodes(apiToken, teamID string) []treeNode {
+	var nodes []treeNode
+	for _, s := range spacesFor(apiToken, teamID, hierarchySpace) {
+		nodes = append(nodes, treeNode{scope: taskScope{kind: scopeSpace, id: s.ID, name: s.Name}, spaceID: s.ID})
+	}
+	return nodes
+}
+
+// singleSpace returns the space new lists and folders go into.
+func singleSpace(apiToken, teamID string) treeNode {
+	nodes := spaceNodes(apiToken, teamID)
+	if len(nodes) != 1 {
+		fmt.Println("Error: choose a space with --space or set default_space.")
+		os.Exit(1)
+	}
+	return nodes[0]
+}
+
+// findContainer finds a folder or list by name or ID in the given spaces.
+// Lists are looked up in the folder given with --folder if there is one.
+func findContainer(apiToken string, spaces []treeNode, kind scopeKind, ref string) treeNode {
+	var found []treeNode
+	for _, space := range spaces {
+		h := newHierarchy()
+		tree := mustFetchSpaceTree(apiToken, space.spaceID)
+		h.spaces = []Space{{ID: space.spaceID, Name: space.scope.name}}
+		h.folders[space.spaceID] = tree.folders
+		h.lists[space.spaceID] = tree.lists
+		for _, f := range tree.folders {
+			h.expanded[nodeKey(scopeFolder, f.ID)] = true
+		}
+		h.expanded[space.key()] = true
+		for _, item := range h.items() {
+			n := item.(treeNode)
+			if n.scope.kind != kind || (n.scope.id != ref && !strings.EqualFold(n.scope.name, ref)) {
+				continue
+			}
+			if kind == scopeList && hierarchyFolder != "" && n.parent.id != hierarchyFolder && !strings.EqualFold(n.parent.name, hierarchyFolder) {
+				continue
+			}
+			found = append(found, n)
+		}
+	}
+	switch len(found) {
+	case 0:
+		fmt.Printf("Error: %s %q not found.\n", kind, ref)
+		os.Exit(1)
+	case 1:
+		return found[0]
+	}
+	fmt.Printf("Error: %d %ss are named %q, use the ID or --space/--folder:\n", len(found), kind, ref)
+	for _, n := range found {
+		fmt.Printf("  %s  %s\n", n.scope.id, n.parent.name)
+	}
+	os.Exit(1)
+	return treeNode{}
+}
+
+// scopeFor resolves the --space, --folder and --list flags of a command to
+// the scope they select. It reports false when none of them is given.
+func scopeFor(apiToken, teamID, space, folder, list string) (taskScope, bool) {
+	switch {
+	case folder != "" || list != "":
+		var nodes []treeNode
+		for _, s := range spacesFor(apiToken, teamID, space) {
+			nodes = append(nodes, treeNode{scope: taskScope{kind: scopeSpace, id: s.ID, name: s.Name}, spaceID: s.ID})
+		}
+		kind, ref := scopeFolder, folder
+		if list != "" {
+			kind, ref = scopeList, list
+		}
+		return findContainer(apiToken, nodes, kind, ref).scope, true
+	case space != "":
+		s := spacesFor(apiToken, teamID, space)[0]
+		return taskScope{kind: scopeSpace, id: s.ID, name: s.Name}, true
+	}
+	return taskScope{}, false
+}
+
+func fetchListTemplates(apiToken, teamID string) []listTemplate {
+	switch msg := fetchListTemplatesCmd(apiToken, teamID)().(type) {
+	case error:
+		fmt.Println("Error:", msg)
+		os.Exit(1)
+	case listTemplatesResponse:
+		return msg.Templates
+	}
+	return nil
+}
+
+func findListTemplate(apiToken, teamID, ref string) listTemplate {
+	for _, t := range fetchListTemplates(apiToken, teamID) {
+		if t.ID == ref || strings.EqualFold(t.Name, ref) {
+			return t
+		}
+	}
+	fmt.Printf("Error: list template %q not found.\n", ref)
+	os.Exit(1)
+	return listTemplate{}
+}
+
+func init() {
+	listsCreateCmd.Flags().StringVar(&containerTemplate, "template", "", "create the list from this list template (name or ID)")
+	listsCmd.AddCommand(listsCreateCmd, listsTemplatesCmd)
+	listsCmd.AddCommand(containerCommands(scopeList)...)
+	foldersCmd.AddCommand(foldersCreateCmd)
+	foldersCmd.AddCommand(containerCommands(scopeFolder)...)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestConfirmationQuestion(t *testing.T) {
+	tests := []struct {
+		kind  containerOpKind
+		scope taskScope
+		want  string
+	}{
+		{deleteOp, taskScope{kind: scopeList, name: "Backlog"}, "Are you sure you want to delete the list 'Backlog' with its tasks?"},
+		{archiveOp, taskScope{kind: scopeList, name: "Backlog"}, "Are you sure you want to archive the list 'Backlog' with its tasks?"},
+		{deleteOp, taskScope{kind: scopeFolder, name: "Sprints"}, "Are you sure you want to delete the folder 'Sprints' with all its lists and tasks?"},
+		{archiveOp, taskScope{kind: scopeFolder, name: "Sprints"}, "Are you sure you want to archive the folder 'Sprints' with all its lists and tasks?"},
+	}
+	for _, tt := range tests {
+		if got := confirmationQuestion(tt.kind, tt.scope); got != tt.want {
+			t.Errorf("confirmationQuestion(%d, %s) = %q, want %q", tt.kind, tt.scope.kind, got, tt.want)
+		}
+	}
+}
+
+func TestTreeActionsLeaveSpacesAlone(t *testing.T) {
+	space := treeNode{scope: taskScope{kind: scopeSpace, id: "100", name: "Engineering"}, spaceID: "100"}
+	m := newModel("pk_1", "1", "", false)
+	for _, k := range []string{"r", "a", "d"} {
+		if _, _, handled := updateTreeActions(typed(k), m, space); handled {
+			t.Errorf("%s on a space was handled", k)
+		}
+	}
+	if _, _, handled := updateTreeActions(typed("n"), m, space); !handled {
+		t.Errorf("n on a space was not handled")
+	}
+}
+
+// useContainers serves a space with the list Backlog and the folder Sprints,
+// and records the changes made to them. Backlog is archived by an update
+// only when archives is true.
+func useContainers(t *testing.T, archives bool) *[]string {
+	var changes []string
+	archived := false
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v2/team/1/space":
+			w.Write([]byte(`{"spaces":[{"id":"100","name":"Engineering"}]}`))
+		case r.URL.Path == "/api/v2/space/100/folder":
+			w.Write([]byte(`{"folders":[{"id":"300","name":"Sprints","lists":[{"id":"901","name":"Sprint 1"}]}]}`))
+		case r.URL.Path == "/api/v2/space/100/list":
+			w.Write([]byte(`{"lists":[{"id":"900","name":"Backlog"}]}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v2/list/900":
+			if archived {
+				w.Write([]byte(`{"id":"900","archived":true}`))
+			} else {
+				w.Write([]byte(`{"id":"900","archived":false}`))
+			}
+		default:
+			changes = append(changes, r.Method+" "+r.URL.Path)
+			archived = archives && r.Method == "PUT"
+			w.Write([]byte(`{}`))
+		}
+	}))
+	useProfile(t)
+	return &changes
+}
+
+// useProfile sets up a config with a profile for the token pk_1 in team 1,
+// for commands that load their credentials.
+func useProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("CLUP_PROFILE", "")
+	cfg := &config{CurrentProfile: "work", Profiles: map[string]*profile{"work": {APIToken: "pk_1", TeamID: "1"}}}
+	if err := cfg.save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runCommand runs cmd with args, answering a confirmation with answer, and
+// returns what it printed.
+func runCommand(t *testing.T, cmd *cobra.Command, answer string, args ...string) string {
+	return printed(t, answer, func() { cmd.Run(cmd, args) })
+}
+
+// printed runs f with input on stdin and returns what it printed.
+func printed(t *testing.T, input string, f func()) string {
+	dir := t.TempDir()
+	in, err := os.Create(filepath.Join(dir, "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in.WriteString(input + "\n")
+	in.Seek(0, io.SeekStart)
+	out, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(stdin, stdout *os.File) { os.Stdin, os.Stdout = stdin, stdout }(os.Stdin, os.Stdout)
+	os.Stdin, os.Stdout = in, out
+	f()
+	data, _ := os.ReadFile(out.Name())
+	return string(data)
+}
+
+// containerCommand returns the list command with the given verb.
+func containerCommand(verb string) *cobra.Command {
+	for _, cmd := range containerCommands(scopeList) {
+		if cmd.Name() == verb {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func TestContainerCommandsConfirm(t *testing.T) {
+	defer func(yes bool) { containerYes = yes }(containerYes)
+	tests := []struct {
+		name   string
+		verb   string
+		yes    bool
+		answer string
+		want   []string
+		output string
+	}{
+		{"delete declined", "delete", false, "n", nil, "Are you sure you want to delete the list 'Backlog' with its tasks? (y/n)"},
+		{"delete confirmed", "delete", false, "y", []string{"DELETE /api/v2/list/900"}, "Deleted list Backlog."},
+		{"delete with --yes", "delete", true, "", []string{"DELETE /api/v2/list/900"}, "Deleted list Backlog."},
+		{"archive with --yes", "archive", true, "", []string{"PUT /api/v2/list/900"}, "Archived list Backlog."},
+	}
+	for _, tt := range tests {
+		changes := useContainers(t, true)
+		cmd := containerCommand(tt.verb)
+		if tt.yes {
+			cmd.Flags().Set("yes", "true")
+		}
+		out := runCommand(t, cmd, tt.answer, "Backlog")
+		if !reflect.DeepEqual(*changes, tt.want) {
+			t.Errorf("%s: changes %q, want %q", tt.name, *changes, tt.want)
+		}
+		if !strings.Contains(out, tt.output) {
+			t.Errorf("%s: printed %q, want %q", tt.name, out, tt.output)
+		}
+		if tt.yes && strings.Contains(out, "(y/n)") {
+			t.Errorf("%s: asked for confirmation: %q", tt.name, out)
+		}
+	}
+}
+
+func TestArchiveContainerChecksArchived(t *testing.T) {
+	scope := taskScope{kind: scopeList, id: "900", name: "Backlog"}
+	useContainers(t, true)
+	if err := archiveContainer("pk_1", scope); err != nil {
+		t.Errorf("archive: %v", err)
+	}
+	useContainers(t, false)
+	if err := archiveContainer("pk_1", scope); err == nil || !strings.Contains(err.Error(), "did not archive Backlog") {
+		t.Errorf("archive that ClickUp ignored: %v, want an error", err)
+	}
+}
@@ -0,0 +1,642 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+// --- CREDENTIAL STORES ---
+
+// credentialSettings selects where API tokens are kept.
+type credentialSettings struct {
+	Store   string `yaml:"store,omitempty"`
+	Command string `yaml:"command,omitempty"`
+	File    string `yaml:"file,omitempty"`
+}
+
+// credentialStore keeps the API token of each account outside of the config
+// file. Accounts are profile names, or defaultAccount without a profile.
+type credentialStore interface {
+	Name() string
+	Get(account string) (string, error)
+	Set(account, token string) error
+	Delete(account string) error
+}
+
+const (
+	defaultAccount  = "default"
+	keyringService  = "clup"
+	pbkdfIterations = 600000
+)
+
+var errNoCredential = errors.New("no token stored")
+
+var credentialStoreNames = []string{"auto", "keyring", "file", "command", "env"}
+
+// newCredentialStore returns the configured store. Interactive stores may
+// prompt on the terminal, which is not possible while the TUI is running.
+func newCredentialStore(c credentialSettings, interactive bool) (credentialStore, error) {
+	switch c.Store {
+	case "", "auto":
+		return autoStore{keyringStore{}, envStore{}}, nil
+	case "keyring":
+		return keyringStore{}, nil
+	case "file":
+		path := c.File
+		if path == "" {
+			path = filepath.Join(configDir(), "credentials.enc")
+		}
+		return &fileStore{path: path, interactive: interactive}, nil
+	case "command":
+		if c.Command == "" {
+			return nil, fmt.Errorf("credentials.command must be set for the command store")
+		}
+		return commandStore{command: c.Command}, nil
+	case "env":
+		return envStore{}, nil
+	}
+	return nil, fmt.Errorf("unknown credential store %q, expected one of %s", c.Store, strings.Join(credentialStoreNames, ", "))
+}
+
+func accountName(profileName string) string {
+	if profileName == "" {
+		return defaultAccount
+	}
+	return profileName
+}
+
+// keyringStore uses the OS keyring: the Secret Service over D-Bus on Linux,
+// the Keychain on macOS and the Credential Manager on Windows.
+type keyringStore struct{}
+
+func (keyringStore) Name() string { return "keyring" }
+
+func (keyringStore) Get(account string) (string, error) {
+	token, err := keyring.Get(keyringService, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", errNoCredential
+	}
+	return token, err
+}
+
+func (keyringStore) Set(account, token string) error {
+	return keyring.Set(keyringService, account, token)
+}
+
+func (keyringStore) Delete(account string) error {
+	err := keyring.Delete(keyringService, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return errNoCredential
+	}
+	return err
+}
+
+// envStore is the plaintext ~/.clup.env file. It only holds the default
+// account; profiles keep plaintext tokens in the config file instead.
+type envStore struct{}
+
+func (envStore) Name() string { return "env file" }
+
+func envFilePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "." // Fallback to current directory
+	}
+	return filepath.Join(home, ".clup.env")
+}
+
+func (envStore) Get(account string) (string, error) {
+	token := os.Getenv("CLICKUP_API_TOKEN")
+	if account != defaultAccount || token == "" {
+		return "", errNoCredential
+	}
+	return token, nil
+}
+
+func (envStore) Set(account, token string) error {
+	if account != defaultAccount {
+		return fmt.Errorf("the env file only holds the default account")
+	}
+	return writeEnvFile(map[string]string{"CLICKUP_API_TOKEN": token})
+}
+
+func (envStore) Delete(account string) error {
+	if account != defaultAccount {
+		return errNoCredential
+	}
+	env, err := godotenv.Read(envFilePath())
+	if errors.Is(err, fs.ErrNotExist) || env["CLICKUP_API_TOKEN"] == "" {
+		return errNoCredential
+	}
+	if err != nil {
+		return err
+	}
+	os.Unsetenv("CLICKUP_API_TOKEN")
+	return writeEnvFile(map[string]string{"CLICKUP_API_TOKEN": ""})
+}
+
+// writeEnvFile updates variables in ~/.clup.env, keeping the other ones.
+// Empty values are removed.
+func writeEnvFile(vars map[string]string) error {
+	env, err := godotenv.Read(envFilePath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if env == nil {
+		env = map[string]string{}
+	}
+	for k, v := range vars {
+		if v == "" {
+			delete(env, k)
+		} else {
+			env[k] = v
+		}
+	}
+	content, err := godotenv.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(envFilePath(), []byte(content+"\n"), 0600)
+}
+
+// autoStore prefers the keyring and falls back to the env file when no
+// keyring is available.
+type autoStore struct {
+	keyring keyringStore
+	env     envStore
+}
+
+func (s autoStore) Name() string { return "auto (keyring, env file)" }
+
+func (s autoStore) Get(account string) (string, error) {
+	if token, err := s.keyring.Get(account); err == nil {
+		return token, nil
+	}
+	return s.env.Get(account)
+}
+
+func (s autoStore) Set(account, token string) error {
+	if err := s.keyring.Set(account, token); err == nil {
+		return nil
+	}
+	return s.env.Set(account, token)
+}
+
+func (s autoStore) Delete(account string) error {
+	errKeyring := s.keyring.Delete(account)
+	errEnv := s.env.Delete(account)
+	if errKeyring != nil && errEnv != nil {
+		return errNoCredential
+	}
+	return nil
+}
+
+// fileStore keeps tokens in a file encrypted with AES-GCM, using a key derived
+// from a passphrase. The passphrase is read from CLUP_PASSPHRASE or prompted
+// for.
+type fileStore struct {
+	path        string
+	interactive bool
+	passphrase  string
+}
+
+type encryptedFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+func (s *fileStore) Name() string { return "encrypted file " + s.path }
+
+func (s *fileStore) Get(account string) (string, error) {
+	tokens, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	token, ok := tokens[account]
+	if !ok {
+		return "", errNoCredential
+	}
+	return token, nil
+}
+
+func (s *fileStore) Set(account, token string) error {
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	tokens[account] = token
+	return s.save(tokens)
+}
+
+func (s *fileStore) Delete(account string) error {
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[account]; !ok {
+		return errNoCredential
+	}
+	delete(tokens, account)
+	return s.save(tokens)
+}
+
+func (s *fileStore) readPassphrase() (string, error) {
+	if s.passphrase != "" {
+		return s.passphrase, nil
+	}
+	if p := os.Getenv("CLUP_PASSPHRASE"); p != "" {
+		s.passphrase = p
+		return p, nil
+	}
+	if !s.interactive || !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("set CLUP_PASSPHRASE to unlock %s", s.path)
+	}
+	fmt.Fprint(os.Stderr, "Passphrase for "+s.path+": ")
+	p, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	s.passphrase = string(p)
+	return s.passphrase, nil
+}
+
+func (s *fileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	passphrase, err := s.readPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdfIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *fileStore) load() (map[string]string, error) {
+	tokens := map[string]string{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	aead, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt %s: wrong passphrase?", s.path)
+	}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *fileStore) save(tokens map[string]string) error {
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	file := encryptedFile{Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	aead, err := s.cipher(file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Data = aead.Seal(nil, file.Nonce, plain, nil)
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
+
+// commandStore runs an external helper, like git credential helpers. The
+// helper is called with "get", "store" or "erase" as its last argument and
+// reads key=value lines on stdin:
+//
+//	service=clup
+//	account=<profile>
+//	token=<token>      (store only)
+//
+// For "get" it prints a token=<token> line, or nothing when it has no token.
+type commandStore struct {
+	command string
+}
+
+func (s commandStore) Name() string { return "command " + s.command }
+
+func (s commandStore) run(op, account, token string) (string, error) {
+	var stdin bytes.Buffer
+	fmt.Fprintf(&stdin, "service=%s\naccount=%s\n", keyringService, account)
+	if token != "" {
+		fmt.Fprintf(&stdin, "token=%s\n", token)
+	}
+	cmd := exec.Command("sh", "-c", s.command+" "+op)
+	cmd.Stdin = &stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("credential helper %s failed: %v %s", op, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func (s commandStore) Get(account string) (string, error) {
+	out, err := s.run("get", account, "")
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if token, ok := strings.CutPrefix(scanner.Text(), "token="); ok && token != "" {
+			return token, nil
+		}
+	}
+	return "", errNoCredential
+}
+
+func (s commandStore) Set(account, token string) error {
+	_, err := s.run("store", account, token)
+	return err
+}
+
+func (s commandStore) Delete(account string) error {
+	_, err := s.run("erase", account, "")
+	return err
+}
+
+// --- LOGIN ---
+
+// saveLogin stores the token of a profile in the configured store, together
+// with the team ID in the config file. Without a profile, the env file store
+// keeps writing ~/.clup.env while the other stores create a "default"
+// profile to remember the team ID.
+func saveLogin(cfg *config, store credentialStore, profileName, apiToken, teamID string) error {
+	if _, isEnv := store.(envStore); isEnv && profileName == "" {
+		return writeEnvFile(map[string]string{"CLICKUP_API_TOKEN": apiToken, "CLICKUP_TEAM_ID": teamID})
+	}
+	if profileName == "" {
+		profileName = defaultAccount
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*profile{}
+	}
+	p, ok := cfg.Profiles[profileName]
+	if !ok {
+		p = &profile{Name: profileName}
+		cfg.Profiles[profileName] = p
+	}
+	p.TeamID = teamID
+	p.APIToken = ""
+	if _, isEnv := store.(envStore); isEnv {
+		p.APIToken = apiToken
+	} else if err := store.Set(profileName, apiToken); err != nil {
+		// Without a keyring the auto store falls back to the env file, which
+		// only holds the default account, so the token stays in the profile.
+		if _, isAuto := store.(autoStore); !isAuto {
+			return err
+		}
+		p.APIToken = apiToken
+	}
+	if cfg.CurrentProfile == "" {
+		cfg.CurrentProfile = profileName
+	}
+	return cfg.save()
+}
+
+// lookupToken returns the token of a profile: a plaintext token from the
+// config file, or the one in the credential store.
+func lookupToken(cfg *config, store credentialStore, profileName string) (string, error) {
+	if p, ok := cfg.Profiles[profileName]; ok && p.APIToken != "" {
+		return p.APIToken, nil
+	}
+	return store.Get(accountName(profileName))
+}
+
+// --- AUTH (CLI) ---
+
+var (
+	loginToken string
+	loginTeam  string
+	loginStore string
+
+	loginOAuth        bool
+	loginClientID     string
+	loginClientSecret string
+	loginNoBrowser    bool
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage the stored API token",
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Store an API token for the active profile",
+	Long: `Store an API token for the active profile (see --profile).
+
+The token is kept in the credential store set with credentials.store in the
+config file, or --store: auto (keyring with the env file as fallback), keyring,
+file (encrypted with a passphrase), command (an external helper) or env.
+
+With --oauth, clup opens the ClickUp authorization page and receives the
+token on a local redirect (http://127.0.0.1:<port>/callback) instead. The
+OAuth app is set with oauth.client_id and oauth.client_secret in the config
+file, --client-id/--client-secret or CLUP_OAUTH_CLIENT_ID/CLUP_OAUTH_CLIENT_SECRET.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+		cfg := settings
+		if loginStore != "" {
+			cfg.Credentials.Store = loginStore
+		}
+		store, err := newCredentialStore(cfg.Credentials, true)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		name := activeProfile(cfg)
+		if name != "" {
+			if _, ok := cfg.Profiles[name]; !ok && name != profileFlag {
+				fmt.Printf("Error: profile %q does not exist.\n", name)
+				os.Exit(1)
+			}
+		}
+		in := bufio.NewReader(os.Stdin)
+		if loginOAuth {
+			loginToken, err = oauthLogin(oauthClient(cfg.OAuth), !loginNoBrowser)
+			if err != nil {
+				fmt.Println("Error logging in:", err)
+				os.Exit(1)
+			}
+		}
+		if loginToken == "" {
+			loginToken = promptSecret(in, "ClickUp API Token: ")
+		}
+		if loginTeam == "" {
+			if p, ok := cfg.Profiles[name]; ok && p.TeamID != "" {
+				loginTeam = p.TeamID
+			} else if loginOAuth {
+				loginTeam, err = chooseTeam(loginToken, func(label string) string { return prompt(in, label) })
+				if err != nil {
+					fmt.Println("Error selecting workspace:", err)
+					os.Exit(1)
+				}
+			} else {
+				loginTeam = prompt(in, "Team ID: ")
+			}
+		}
+		if loginToken == "" || loginTeam == "" {
+			fmt.Println("Error: API token and team ID are required.")
+			os.Exit(1)
+		}
+		if err := saveLogin(cfg, store, name, loginToken, loginTeam); err != nil {
+			fmt.Println("Error saving token:", err)
+			os.Exit(1)
+		}
+		if loginStore != "" {
+			if err := cfg.save(); err != nil {
+				fmt.Println("Error saving config:", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Printf("Saved token for %s in %s.\n", accountName(name), store.Name())
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the API token of the active profile",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+		cfg := settings
+		store, err := newCredentialStore(cfg.Credentials, true)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		name := activeProfile(cfg)
+		removed := false
+		if p, ok := cfg.Profiles[name]; ok && p.APIToken != "" {
+			p.APIToken = ""
+			if err := cfg.save(); err != nil {
+				fmt.Println("Error saving config:", err)
+				os.Exit(1)
+			}
+			removed = true
+		}
+		switch err := store.Delete(accountName(name)); {
+		case err == nil:
+			removed = true
+		case !errors.Is(err, errNoCredential):
+			fmt.Println("Error removing token:", err)
+			os.Exit(1)
+		}
+		if !removed {
+			fmt.Printf("No token stored for %s.\n", accountName(name))
+			return
+		}
+		fmt.Printf("Removed token for %s.\n", accountName(name))
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where the API token comes from and whether it works",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID, name, err := credentials()
+		if err != nil {
+			fmt.Println("Error loading credentials:", err)
+			os.Exit(1)
+		}
+		store, _ := newCredentialStore(settings.Credentials, true)
+		fmt.Printf("Profile: %s\n", orDash(name))
+		fmt.Printf("Store:   %s\n", store.Name())
+		fmt.Printf("Team:    %s\n", orDash(teamID))
+		if apiToken == "" {
+			fmt.Println("Token:   not set (run `clup auth login`)")
+			os.Exit(1)
+		}
+		fmt.Printf("Token:   %s (%s)\n", maskToken(strings.TrimPrefix(apiToken, bearerPrefix)), tokenKind(apiToken))
+		switch msg := fetchUserCmd(apiToken)().(type) {
+		case error:
+			fmt.Println("Status:  token rejected:", msg)
+			os.Exit(1)
+		case UserResponse:
+			fmt.Printf("Status:  logged in as %s (%s)\n", msg.User.Username, msg.User.Email)
+		}
+	},
+}
+
+func maskToken(token string) string {
+	if len(token) <= 10 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:6] + "…" + token[len(token)-4:]
+}
+
+// promptSecret reads a line without echoing it when stdin is a terminal.
+func promptSecret(in *bufio.Reader, label string) string {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return prompt(in, label)
+	}
+	fmt.Print(label)
+	secret, _ := term.ReadPassword(os.Stdin.Fd())
+	fmt.Println()
+	return strings.TrimSpace(string(secret))
+}
+
+func init() {
+	authLoginCmd.Flags().StringVar(&loginToken, "token", "", "ClickUp API token (prompted for when missing)")
+	authLoginCmd.Flags().StringVar(&loginTeam, "team", "", "ClickUp team (workspace) ID")
+	authLoginCmd.Flags().StringVar(&loginStore, "store", "", "credential store to use and remember: "+strings.Join(credentialStoreNames, ", "))
+	authLoginCmd.Flags().BoolVar(&loginOAuth, "oauth", false, "log in through the browser with a ClickUp OAuth app")
+	authLoginCmd.Flags().StringVar(&loginClientID, "client-id", "", "OAuth app client ID")
+	authLoginCmd.Flags().StringVar(&loginClientSecret, "client-secret", "", "OAuth app client secret")
+	authLoginCmd.Flags().BoolVar(&loginNoBrowser, "no-browser", false, "print the authorization URL without opening a browser")
+	authCmd.AddCommand(authLoginCmd, authLogoutCmd, authStatusCmd)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// credentialHelper is a helper script that keeps each token in a file named
+// after the account.
+const credentialHelper = `#!/bin/sh
+dir=$(dirname "$0")/tokens
+mkdir -p "$dir"
+while IFS='=' read -r k v; do
+	case $k in
+	service) [ "$v" = clup ] || exit 3 ;;
+	account) account=$v ;;
+	token) token=$v ;;
+	esac
+done
+case $1 in
+get) [ -f "$dir/$account" ] && echo "token=$(cat "$dir/$account")" ;;
+store) printf '%s' "$token" > "$dir/$account" ;;
+erase) rm -f "$dir/$account" ;;
+*) echo "unknown operation $1" >&2; exit 2 ;;
+esac
+exit 0
+`
+
+func TestCommandStore(t *testing.T) {
+	dir := t.TempDir()
+	helper := filepath.Join(dir, "helper")
+	if err := os.WriteFile(helper, []byte(credentialHelper), 0700); err != nil {
+		t.Fatal(err)
+	}
+	store, err := newCredentialStore(credentialSettings{Store: "command", Command: helper}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("work"); !errors.Is(err, errNoCredential) {
+		t.Fatalf("Get before Set = %v, want errNoCredential", err)
+	}
+	if err := store.Set("work", "pk_secret"); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := store.Get("work"); err != nil || token != "pk_secret" {
+		t.Fatalf("Get = %q, %v, want %q", token, err, "pk_secret")
+	}
+	if _, err := store.Get("home"); !errors.Is(err, errNoCredential) {
+		t.Errorf("Get of another account = %v, want errNoCredential", err)
+	}
+	if err := store.Delete("work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("work"); !errors.Is(err, errNoCredential) {
+		t.Errorf("Get after Delete = %v, want errNoCredential", err)
+	}
+
+	failing := commandStore{command: helper + " bad"}
+	if _, err := failing.Get("work"); err == nil || !strings.Contains(err.Error(), "unknown operation bad") {
+		t.Errorf("Get with a failing helper = %v, want the helper's error", err)
+	}
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	// Time zones from ClickUp profiles have to load on systems without a
+	// zoneinfo database as well.
+	_ "time/tzdata"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- DATES ---
+
+// Due and start dates are typed in as "tomorrow", "next fri 9am", "in 3d" or
+// "2026-11-01 14:00", and read in the time zone of the user's ClickUp
+// profile. Dates without a time of day are sent as midnight with the
+// due_date_time or start_date_time flag off, so ClickUp shows them as dates.
+
+// taskDate is a due or start date. The zero value is no date.
+type taskDate struct {
+	time    time.Time
+	hasTime bool
+}
+
+func (d taskDate) isZero() bool { return d.time.IsZero() }
+
+func (d taskDate) String() string {
+	switch {
+	case d.isZero():
+		return ""
+	case d.hasTime:
+		return d.time.Format("Mon, Jan 2 2006 15:04")
+	}
+	return d.time.Format("Mon, Jan 2 2006")
+}
+
+// input is the date in a form parseDate reads back.
+func (d taskDate) input() string {
+	switch {
+	case d.isZero():
+		return ""
+	case d.hasTime:
+		return d.time.Format("2006-01-02 15:04")
+	}
+	return d.time.Format("2006-01-02")
+}
+
+// sameAs reports whether d is the date ClickUp sent as ms.
+func (d taskDate) sameAs(ms string) bool {
+	t, ok := msTime(ms)
+	if !ok {
+		return d.isZero()
+	}
+	return !d.isZero() && d.time.UnixMilli() == t.UnixMilli()
+}
+
+// taskDateOf reads a date sent by ClickUp. Dates at midnight in loc are taken
+// to have no time of day.
+func taskDateOf(ms string, loc *time.Location) taskDate {
+	t, ok := msTime(ms)
+	if !ok {
+		return taskDate{}
+	}
+	t = t.In(loc)
+	return taskDate{time: t, hasTime: t.Hour() != 0 || t.Minute() != 0}
+}
+
+// setDateField puts a date into a task payload as name ("due" or "start"). A
+// zero date clears it.
+func setDateField(fields map[string]interface{}, name string, d taskDate) {
+	if d.isZero() {
+		fields[name+"_date"] = nil
+		return
+	}
+	fields[name+"_date"] = d.time.UnixMilli()
+	fields[name+"_date_time"] = d.hasTime
+}
+
+// userLocation is the time zone of the user's ClickUp profile, or the local
+// one when it is unknown.
+func userLocation(u *User) *time.Location {
+	if u != nil && u.Timezone != "" {
+		if loc, err := time.LoadLocation(u.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+var (
+	relativeDateRe = regexp.MustCompile(`^(?:in\s+|\+)(\d+)\s*([a-z]+)$`)
+	timeOfDayRe    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// dateLayouts are the dates parseDate reads besides the named days. Month
+// names may be in any case; dates without a year are the next such day.
+var dateLayouts = []string{"2006-01-02", "Jan 2 2006", "Jan 2, 2006", "2 Jan 2006", "Jan 2", "2 Jan"}
+
+// parseDate reads a date relative to now, in the time zone of now. An empty
+// string, "none" or "-" is no date.
+//
+// Accepted are today, tomorrow, yesterday, weekdays ("fri" and "next fri"
+// both mean the coming Friday), "next week", "next month", offsets such as
+// "in 3d", "+2w" or "in 90m", and dates such as "2026-11-01" or "Nov 1".
+// Days may be followed by a time: "tomorrow 9am", "fri at 14:30",
+// "2026-11-01 14:00".
+func parseDate(s string, now time.Time) (taskDate, error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	switch s {
+	case "", "none", "-":
+		return taskDate{}, nil
+	}
+	if m := relativeDateRe.FindStringSubmatch(s); m != nil {
+		return offsetDate(now, m[1], m[2])
+	}
+	if t, err := time.ParseInLocation("2006-01-02t15:04", s, now.Location()); err == nil {
+		return taskDate{time: t, hasTime: true}, nil
+	}
+
+	// A day, a time of day or both.
+	day, clock := s, ""
+	fields := strings.Fields(s)
+	if n := len(fields); n > 1 && isTimeOfDay(fields[n-1]) {
+		day, clock = strings.Join(fields[:n-1], " "), fields[n-1]
+		day = strings.TrimSpace(strings.TrimSuffix(day, " at"))
+	} else if isTimeOfDay(s) {
+		day, clock = "today", s
+	}
+	date, err := parseDay(day, now)
+	if err != nil {
+		return taskDate{}, err
+	}
+	if clock == "" {
+		return taskDate{time: date}, nil
+	}
+	hour, minute, err := parseTimeOfDay(clock)
+	if err != nil {
+		return taskDate{}, err
+	}
+	y, mo, d := date.Date()
+	return taskDate{time: time.Date(y, mo, d, hour, minute, 0, 0, date.Location()), hasTime: true}, nil
+}
+
+// parseDay returns the start of a named day or a date.
+func parseDay(day string, now time.Time) (time.Time, error) {
+	loc := now.Location()
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, loc)
+	switch day {
+	case "today", "tonight":
+		return today, nil
+	case "tomorrow", "tmr":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next week":
+		return today.AddDate(0, 0, 7), nil
+	case "next month":
+		return today.AddDate(0, 1, 0), nil
+	}
+	if wd, ok := weekdays[strings.TrimPrefix(strings.TrimPrefix(day, "next "), "this ")]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, day, loc)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			t = time.Date(y, t.Month(), t.Day(), 0, 0, 0, 0, loc)
+			if t.Before(today) {
+				t = t.AddDate(1, 0, 0)
+			}
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("can't read the date %q", day)
+}
+
+// offsetDate is now moved by n units. Minutes and hours give a time of day,
+// the larger units only a day.
+func offsetDate(now time.Time, n, unit string) (taskDate, error) {
+	count, _ := strconv.Atoi(n)
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+	switch unit {
+	case "m", "min", "mins", "minute", "minutes":
+		return taskDate{time: now.Add(time.Duration(count) * time.Minute).Truncate(time.Minute), hasTime: true}, nil
+	case "h", "hr", "hrs", "hour", "hours":
+		return taskDate{time: now.Add(time.Duration(count) * time.Hour).Truncate(time.Minute), hasTime: true}, nil
+	case "d", "day", "days":
+		return taskDate{time: today.AddDate(0, 0, count)}, nil
+	case "w", "wk", "wks", "week", "weeks":
+		return taskDate{time: today.AddDate(0, 0, 7*count)}, nil
+	case "mo", "month", "months":
+		return taskDate{time: today.AddDate(0, count, 0)}, nil
+	case "y", "year", "years":
+		return taskDate{time: today.AddDate(count, 0, 0)}, nil
+	}
+	return taskDate{}, fmt.Errorf("unknown unit %q, expected m, h, d, w, mo or y", unit)
+}
+
+// isTimeOfDay tells times from day numbers: "9am" and "9:00" are times, "9"
+// is not.
+func isTimeOfDay(s string) bool {
+	return strings.ContainsAny(s, ":apm") && timeOfDayRe.MatchString(s)
+}
+
+// parseTimeOfDay reads "14:30", "9am" or "3:15pm".
+func parseTimeOfDay(s string) (int, int, error) {
+	m := timeOfDayRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, fmt.Errorf("can't read the time %q", s)
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("can't read the time %q", s)
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, fmt.Errorf("can't read the time %q", s)
+	}
+	return hour, minute, nil
+}
+
+// parseDates reads a due and a start date, which may not come after it.
+func parseDates(due, start string, now time.Time) (taskDate, taskDate, error) {
+	d, err := parseDate(due, now)
+	if err != nil {
+		return taskDate{}, taskDate{}, fmt.Errorf("due date: %v", err)
+	}
+	s, err := parseDate(start, now)
+	if err != nil {
+		return taskDate{}, taskDate{}, fmt.Errorf("start date: %v", err)
+	}
+	if !d.isZero() && !s.isZero() && s.time.After(d.time) {
+		return taskDate{}, taskDate{}, fmt.Errorf("the start date is after the due date")
+	}
+	return d, s, nil
+}
+
+// editDateFields are the changes to the dates made in the edit view.
+func (m model) editDateFields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	if !m.editDue.sameAs(m.selectedTask.DueDate) {
+		setDateField(fields, "due", m.editDue)
+	}
+	if !m.editStart.sameAs(m.selectedTask.StartDate) {
+		setDateField(fields, "start", m.editStart)
+	}
+	return fields
+}
+
+// --- UPDATE & VIEW (DATES) ---
+
+// The date form has a due and a start date field, each with a preview of the
+// date it reads. It is a step of the creation wizard, and is opened with d
+// from the edit view.
+
+const (
+	dueInput int = iota
+	startInput
+)
+
+func (m model) startTaskDates(due, start taskDate, focus int) (model, tea.Cmd) {
+	if m.state != taskDatesView {
+		m.previousState = m.state
+	}
+	m.state = taskDatesView
+	m.dateErr = nil
+	m.dateInputs = make([]textinput.Model, 2)
+	for i, d := range []taskDate{due, start} {
+		input := textinput.New()
+		input.Placeholder = "tomorrow, next fri 9am, in 3d"
+		input.Prompt = ""
+		input.Width = 40
+		input.SetValue(d.input())
+		m.dateInputs[i] = input
+	}
+	m.dateFocus = focus
+	m.dateInputs[focus].Focus()
+	if m.currentUser == nil {
+		return m, fetchUserCmd(m.apiToken)
+	}
+	return m, nil
+}
+
+// setUser stores the user whose time zone dates are read in. The dates of
+// the edited task that haven't been changed are read again in that zone, so
+// that a date at midnight there stays a date without a time of day.
+func (m *model) setUser(u User) {
+	m.currentUser = &u
+	loc := userLocation(m.currentUser)
+	if m.editDue.sameAs(m.selectedTask.DueDate) {
+		m.editDue = taskDateOf(m.selectedTask.DueDate, loc)
+	}
+	if m.editStart.sameAs(m.selectedTask.StartDate) {
+		m.editStart = taskDateOf(m.selectedTask.StartDate, loc)
+	}
+}
+
+// datesNow is the current time in the user's time zone.
+func (m model) datesNow() time.Time {
+	return time.Now().In(userLocation(m.currentUser))
+}
+
+// listUserMsg is the user whose time zone the task list shows due dates in.
+type listUserMsg User
+
+// fetchListUserCmd loads the user for the task list. Until it arrives, or
+// when it fails, due dates are shown in the local time zone.
+func fetchListUserCmd(apiToken string) tea.Cmd {
+	return func() tea.Msg {
+		if msg, ok := fetchUserCmd(apiToken)().(UserResponse); ok {
+			return listUserMsg(msg.User)
+		}
+		return nil
+	}
+}
+
+func updateTaskDates(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	editing := m.previousState == editTaskView
+	switch msg := msg.(type) {
+	case UserResponse:
+		// Inputs still showing the task's dates are filled in again in the
+		// user's time zone.
+		shown := []string{m.editDue.input(), m.editStart.input()}
+		m.setUser(msg.User)
+		if editing {
+			for i, d := range []taskDate{m.editDue, m.editStart} {
+				if m.dateInputs[i].Value() == shown[i] {
+					m.dateInputs[i].SetValue(d.input())
+				}
+			}
+		}
+		return m, nil
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case tea.KeyMsg:
+		switch {
+		case msg.Type == tea.KeyEsc && editing:
+			m.state = editTaskView
+			return m, nil
+		case isBackKey(msg) && !editing:
+			return m.backCreateTask()
+		}
+		switch msg.String() {
+		case "tab", "down", "up":
+			m.dateInputs[m.dateFocus].Blur()
+			m.dateFocus = 1 - m.dateFocus
+			return m, m.dateInputs[m.dateFocus].Focus()
+		case "enter":
+			due, start, err := parseDates(m.dateInputs[dueInput].Value(), m.dateInputs[startInput].Value(), m.datesNow())
+			if err != nil {
+				m.dateErr = err
+				return m, nil
+			}
+			if editing {
+				m.editDue, m.editStart = due, start
+				m.state = editTaskView
+				return m, nil
+			}
+			m.newTaskDue, m.newTaskStart = due, start
+			return m.advanceCreateTask(func(m model) (model, tea.Cmd) {
+				return m.startCreateTaskReview(), nil
+			})
+		}
+	}
+	var cmd tea.Cmd
+	m.dateErr = nil
+	m.dateInputs[m.dateFocus], cmd = m.dateInputs[m.dateFocus].Update(msg)
+	return m, cmd
+}
+
+func (m model) viewTaskDates() string {
+	var b strings.Builder
+	if m.previousState == editTaskView {
+		b.WriteString(titleStyle.Render("Dates of " + m.selectedTask.Name))
+	} else {
+		b.WriteString(titleStyle.Render("Set Dates"))
+	}
+	b.WriteString("\n\n")
+	now := m.datesNow()
+	for i, label := range []string{"Due", "Start"} {
+		line := fmt.Sprintf("%-7s %s", label+":", m.dateInputs[i].View())
+		if i == m.dateFocus {
+			line = focusedStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+		d, err := parseDate(m.dateInputs[i].Value(), now)
+		switch {
+		case err != nil:
+			b.WriteString(failureStyle.Render("          "+err.Error()) + "\n\n")
+		case d.isZero():
+			b.WriteString(blurredStyle.Render("          no date") + "\n\n")
+		default:
+			b.WriteString(blurredStyle.Render("          "+d.String()) + "\n\n")
+		}
+	}
+	if m.dateErr != nil {
+		b.WriteString(failureStyle.Render("  "+m.dateErr.Error()) + "\n\n")
+	}
+	b.WriteString(blurredStyle.Render("  Times are in " + now.Location().String()))
+	b.WriteString(helpStyle.Render("\n\ntab: switch field • enter: confirm • empty: no date • esc: back"))
+	return appStyle.Render(b.String())
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	now := time.Date(2026, time.March, 4, 10, 30, 0, 0, loc) // a Wednesday
+	day := func(m time.Month, d int) taskDate {
+		return taskDate{time: time.Date(2026, m, d, 0, 0, 0, 0, loc)}
+	}
+	at := func(m time.Month, d, h, mi int) taskDate {
+		return taskDate{time: time.Date(2026, m, d, h, mi, 0, 0, loc), hasTime: true}
+	}
+	tests := []struct {
+		in      string
+		want    taskDate
+		wantErr bool
+	}{
+		{in: "", want: taskDate{}},
+		{in: "none", want: taskDate{}},
+		{in: "-", want: taskDate{}},
+		{in: "today", want: day(time.March, 4)},
+		{in: "Tomorrow", want: day(time.March, 5)},
+		{in: "yesterday", want: day(time.March, 3)},
+		{in: "fri", want: day(time.March, 6)},
+		{in: "next friday", want: day(time.March, 6)},
+		{in: "wed", want: day(time.March, 11)},
+		{in: "next week", want: day(time.March, 11)},
+		{in: "next month", want: day(time.April, 4)},
+		{in: "in 3d", want: day(time.March, 7)},
+		{in: "+2w", want: day(time.March, 18)},
+		{in: "in 90m", want: at(time.March, 4, 12, 0)},
+		{in: "+1h", want: at(time.March, 4, 11, 30)},
+		{in: "2026-11-01", want: day(time.November, 1)},
+		{in: "2026-11-01 14:00", want: at(time.November, 1, 14, 0)},
+		{in: "2026-11-01T14:00", want: at(time.November, 1, 14, 0)},
+		{in: "Nov 1", want: day(time.November, 1)},
+		{in: "1 nov", want: day(time.November, 1)},
+		{in: "Feb 1", want: taskDate{time: time.Date(2027, time.February, 1, 0, 0, 0, 0, loc)}},
+		{in: "Feb 1, 2026", want: day(time.February, 1)},
+		{in: "tomorrow 9am", want: at(time.March, 5, 9, 0)},
+		{in: "fri at 14:30", want: at(time.March, 6, 14, 30)},
+		{in: "3:15pm", want: at(time.March, 4, 15, 15)},
+		{in: "12am", want: at(time.March, 4, 0, 0)},
+		{in: "someday", wantErr: true},
+		{in: "in 3 fortnights", wantErr: true},
+		{in: "tomorrow 25:00", wantErr: true},
+		{in: "tomorrow 13pm", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.in, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseDate(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.time.Equal(tt.want.time) || got.hasTime != tt.want.hasTime {
+			t.Errorf("parseDate(%q) = %v (time %v), want %v (time %v)", tt.in, got.time, got.hasTime, tt.want.time, tt.want.hasTime)
+		}
+	}
+}
+
+func TestParseDatesOrder(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 10, 0, 0, 0, time.UTC)
+	if _, _, err := parseDates("tomorrow", "fri", now); err == nil {
+		t.Error("parseDates accepted a start date after the due date")
+	}
+	if _, _, err := parseDates("fri", "tomorrow", now); err != nil {
+		t.Errorf("parseDates: %v", err)
+	}
+}
+
+func TestSetUserReadsEditDatesInProfileZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	ms := func(t time.Time) string { return strconv.FormatInt(t.UnixMilli(), 10) }
+	due := time.Date(2026, time.March, 6, 0, 0, 0, 0, tokyo)
+	start := time.Date(2026, time.March, 2, 9, 30, 0, 0, tokyo)
+	m := model{selectedTask: Task{DueDate: ms(due), StartDate: ms(start)}}
+	m.editDue = taskDateOf(m.selectedTask.DueDate, time.UTC)
+	m.editStart = taskDateOf(m.selectedTask.StartDate, time.UTC)
+	if !m.editDue.hasTime {
+		t.Fatal("midnight in Tokyo read as a date without a time in UTC")
+	}
+
+	m.setUser(User{Timezone: "Asia/Tokyo"})
+	if m.editDue.hasTime || m.editDue.input() != "2026-03-06" {
+		t.Errorf("due date = %q, want the date 2026-03-06", m.editDue.input())
+	}
+	if !m.editStart.hasTime || m.editStart.input() != "2026-03-02 09:30" {
+		t.Errorf("start date = %q, want 2026-03-02 09:30", m.editStart.input())
+	}
+	if fields := m.editDateFields(); len(fields) != 0 {
+		t.Errorf("unchanged dates would be sent: %v", fields)
+	}
+
+	// A date the user changed is left alone.
+	m.editDue = taskDate{time: time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC)}
+	m.setUser(User{Timezone: "Asia/Tokyo"})
+	if got := m.editDue.time; !got.Equal(time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("changed due date became %v", got)
+	}
+}
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// --- EXPORT ---
+
+// An export holds every task of a space, folder or list, subtasks included,
+// with its description, checklists and comments. It is written as a
+// directory of Markdown files, one per task, as a CSV file or as a JSON
+// archive.
+
+// exportedTask is a task with its comments, oldest first, as task show -o
+// json prints it.
+type exportedTask struct {
+	Task
+	Comments []Comment `json:"comments"`
+}
+
+// taskDescription is the description of a task, in Markdown when ClickUp
+// sent it.
+func taskDescription(task Task) string {
+	if task.MarkdownContent != "" {
+		return task.MarkdownContent
+	}
+	return task.Content
+}
+
+// fetchExport loads the tasks of a scope, then the details and comments of
+// each of them. Tasks whose details or comments fail to load are left out
+// and returned as failures.
+func fetchExport(apiToken, teamID string, scope taskScope, closed bool, concurrency int) ([]exportedTask, []bulkResult, error) {
+	query := scope.query()
+	query.Set("subtasks", "true")
+	if closed {
+		query.Set("include_closed", "true")
+	}
+	msg := fetchFilteredTasksCmd(apiToken, teamID, query)()
+	if err, ok := msg.(error); ok {
+		return nil, nil, err
+	}
+	tasks := msg.(TasksResponse).Tasks
+	exported := make([]exportedTask, len(tasks))
+	errs := make([]error, len(tasks))
+	forEachConcurrent(len(tasks), concurrency, func(i int) {
+		switch msg := fetchTaskRefCmd(apiToken, teamID, tasks[i].ID)().(type) {
+		case error:
+			errs[i] = msg
+			return
+		case Task:
+			exported[i].Task = msg
+		}
+		comments, err := fetchAllComments(apiToken, tasks[i].ID)
+		if err != nil {
+			errs[i] = fmt.Errorf("fetch comments failed: %w", err)
+			return
+		}
+		exported[i].Comments = latestComments(comments, -1)
+	})
+	var loaded []exportedTask
+	var failures []bulkResult
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, bulkResult{task: tasks[i], err: err})
+		} else {
+			loaded = append(loaded, exported[i])
+		}
+	}
+	return loaded, failures, nil
+}
+
+// fetchAllComments loads every comment of a task, newest first. ClickUp sends
+// them a page at a time; the next page starts at the oldest comment of the
+// previous one, and the last page is empty.
+func fetchAllComments(apiToken, taskID string) ([]Comment, error) {
+	var comments []Comment
+	seen := make(map[string]bool)
+	var query neturl.Values
+	for {
+		var page []Comment
+		switch msg := fetchCommentPageCmd(apiToken, taskID, query)().(type) {
+		case error:
+			return nil, msg
+		case CommentsResponse:
+			page = msg.Comments
+		}
+		added := false
+		for _, c := range page {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				comments = append(comments, c)
+				added = true
+			}
+		}
+		if !added {
+			return comments, nil
+		}
+		oldest := page[len(page)-1]
+		query = neturl.Values{"start": {oldest.Date}, "start_id": {oldest.ID}}
+	}
+}
+
+// exportDate formats a task date in loc, without a time when it has none.
+func exportDate(ms string, loc *time.Location) string {
+	d := taskDateOf(ms, loc)
+	switch {
+	case d.isZero():
+		return ""
+	case d.hasTime:
+		return d.time.Format(time.RFC3339)
+	}
+	return d.time.Format("2006-01-02")
+}
+
+// exportTime formats a timestamp in loc.
+func exportTime(ms string, loc *time.Location) string {
+	if t, ok := msTime(ms); ok {
+		return t.In(loc).Format(time.RFC3339)
+	}
+	return ""
+}
+
+func assigneeNames(task Task) []string {
+	var names []string
+	for _, a := range task.Assignees {
+		names = append(names, a.Username)
+	}
+	return names
+}
+
+func tagNames(task Task) []string {
+	var names []string
+	for _, t := range task.Tags {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func exportPriority(task Task) string {
+	if p := taskPriorityValue(task); p != 0 {
+		return priorityName(p)
+	}
+	return ""
+}
+
+// --- EXPORT (MARKDOWN) ---
+
+// frontMatter is the YAML header of an exported Markdown file.
+type frontMatter struct {
+	ID        string   `yaml:"id"`
+	CustomID  string   `yaml:"custom_id,omitempty"`
+	Name      string   `yaml:"name"`
+	Status    string   `yaml:"status"`
+	Priority  string   `yaml:"priority,omitempty"`
+	Assignees []string `yaml:"assignees,omitempty"`
+	Tags      []string `yaml:"tags,omitempty"`
+	List      string   `yaml:"list,omitempty"`
+	Folder    string   `yaml:"folder,omitempty"`
+	Parent    string   `yaml:"parent,omitempty"`
+	Subtasks  []string `yaml:"subtasks,omitempty"`
+	Start     string   `yaml:"start,omitempty"`
+	Due       string   `yaml:"due,omitempty"`
+	Created   string   `yaml:"created,omitempty"`
+	Updated   string   `yaml:"updated,omitempty"`
+	URL       string   `yaml:"url,omitempty"`
+}
+
+// markdownFile is the name of the file of a task, which starts with its ID
+// so that it stays unique.
+func markdownFile(task Task) string {
+	if slug := slugify(task.Name); slug != "" {
+		return task.ID + "-" + slug + ".md"
+	}
+	return task.ID + ".md"
+}
+
+// writeMarkdownExport writes a Markdown file for every task into dir. Subtasks
+// link to their own files.
+func writeMarkdownExport(dir string, tasks []exportedTask, loc *time.Location) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	files := make(map[string]string)
+	for _, t := range tasks {
+		files[t.ID] = markdownFile(t.Task)
+	}
+	for _, t := range tasks {
+		if err := os.WriteFile(filepath.Join(dir, files[t.ID]), markdownTask(t, files, loc), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func markdownTask(t exportedTask, files map[string]string, loc *time.Location) []byte {
+	fm := frontMatter{
+		ID:        t.ID,
+		CustomID:  t.CustomID,
+		Name:      t.Name,
+		Status:    t.Status.Status,
+		Priority:  exportPriority(t.Task),
+		Assignees: assigneeNames(t.Task),
+		Tags:      tagNames(t.Task),
+		List:      t.List.Name,
+		Folder:    taskFolder(t.Task),
+		Parent:    t.Parent,
+		Start:     exportDate(t.StartDate, loc),
+		Due:       exportDate(t.DueDate, loc),
+		Created:   exportTime(t.DateCreated, loc),
+		Updated:   exportTime(t.DateUpdated, loc),
+		URL:       t.URL,
+	}
+	for _, sub := range t.Subtasks {
+		fm.Subtasks = append(fm.Subtasks, sub.ID)
+	}
+	header, _ := yaml.Marshal(fm)
+
+	var b strings.Builder
+	b.WriteString("---\n" + string(header) + "---\n\n# " + t.Name + "\n")
+	if description := strings.TrimSpace(taskDescription(t.Task)); description != "" {
+		b.WriteString("\n" + description + "\n")
+	}
+	if len(t.Checklists) > 0 {
+		b.WriteString("\n## Checklists\n")
+		for _, cl := range t.Checklists {
+			b.WriteString("\n### " + cl.Name + "\n\n")
+			for _, item := range cl.Items {
+				box := "[ ]"
+				if item.Resolved {
+					box = "[x]"
+				}
+				b.WriteString("- " + box + " " + item.Name + "\n")
+			}
+		}
+	}
+	if len(t.Subtasks) > 0 {
+		b.WriteString("\n## Subtasks\n\n")
+		for _, sub := range t.Subtasks {
+			name := sub.Name
+			if file, ok := files[sub.ID]; ok {
+				name = "[" + name + "](" + file + ")"
+			}
+			b.WriteString("- " + name + " (" + sub.Status.Status + ")\n")
+		}
+	}
+	if len(t.Comments) > 0 {
+		b.WriteString("\n## Comments\n")
+		for _, c := range t.Comments {
+			b.WriteString("\n### " + c.User.Username + " · " + c.Time().In(loc).Format("2006-01-02 15:04") + "\n\n")
+			b.WriteString(strings.TrimSpace(c.Text()) + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// --- EXPORT (CSV) ---
+
+var csvHeader = []string{
+	"id", "custom_id", "name", "status", "priority", "assignees", "tags",
+	"folder", "list", "parent", "start", "due", "created", "updated", "url",
+	"description", "checklists", "subtasks", "comments",
+}
+
+// writeCSVExport writes a row per task. Checklist items, subtasks and comments
+// are written one per line within their cell.
+func writeCSVExport(out io.Writer, tasks []exportedTask, loc *time.Location) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		var checklists, subtasks, comments []string
+		for _, cl := range t.Checklists {
+			for _, item := range cl.Items {
+				box := "[ ]"
+				if item.Resolved {
+					box = "[x]"
+				}
+				checklists = append(checklists, cl.Name+": "+box+" "+item.Name)
+			}
+		}
+		for _, sub := range t.Subtasks {
+			subtasks = append(subtasks, sub.ID+" "+sub.Name)
+		}
+		for _, c := range t.Comments {
+			comments = append(comments, c.Time().In(loc).Format("2006-01-02 15:04")+" "+c.User.Username+": "+strings.TrimSpace(c.Text()))
+		}
+		row := []string{
+			t.ID, t.CustomID, t.Name, t.Status.Status, exportPriority(t.Task),
+			strings.Join(assigneeNames(t.Task), ", "), strings.Join(tagNames(t.Task), ", "),
+			taskFolder(t.Task), t.List.Name, t.Parent,
+			exportDate(t.StartDate, loc), exportDate(t.DueDate, loc),
+			exportTime(t.DateCreated, loc), exportTime(t.DateUpdated, loc), t.URL,
+			strings.TrimSpace(taskDescription(t.Task)),
+			strings.Join(checklists, "\n"), strings.Join(subtasks, "\n"), strings.Join(comments, "\n"),
+		}
+		for i, cell := range row {
+			row[i] = csvCell(cell)
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvCell keeps spreadsheets from running a cell as a formula: text that
+// starts like one gets a ' in front, which they hide.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// --- EXPORT (JSON) ---
+
+// exportArchive is the JSON archive of an export.
+type exportArchive struct {
+	ExportedAt time.Time `json:"exported_at"`
+	Scope      struct {
+		Kind string `json:"kind"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"scope"`
+	Tasks []exportedTask `json:"tasks"`
+}
+
+func writeJSONExport(out io.Writer, scope taskScope, tasks []exportedTask) error {
+	archive := exportArchive{ExportedAt: time.Now().UTC(), Tasks: tasks}
+	archive.Scope.Kind, archive.Scope.ID, archive.Scope.Name = scope.kind.String(), scope.id, scope.name
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(archive)
+}
+
+// --- EXPORT (CLI) ---
+
+var (
+	exportSpace       string
+	exportFolder      string
+	exportList        string
+	exportFormat      string
+	exportOut         string
+	exportNoClosed    bool
+	exportConcurrency int
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the tasks of a space, folder or list",
+	Long: `Export every task of a space, folder or list, subtasks and closed tasks
+included, with its description, checklists and comments.
+
+  md    a directory of Markdown files, one per task, with YAML front matter
+  csv   a CSV file with a row per task
+  json  a JSON archive with the tasks as ClickUp sends them
+
+Markdown is written to --out, or to a directory named after the space,
+folder or list. CSV and JSON are written to --out, or to stdout.`,
+	Example: `  clup export --space Engineering --out engineering
+  clup export --folder Backend --format csv > backend.csv
+  clup export --list Sprint --format json --out sprint.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exportFormat != "md" && exportFormat != "csv" && exportFormat != "json" {
+			fmt.Printf("Error: unknown export format %q, expected md, csv or json.\n", exportFormat)
+			os.Exit(1)
+		}
+		apiToken, teamID := requireCredentials()
+		scope, ok := scopeFor(apiToken, teamID, exportSpace, exportFolder, exportList)
+		if !ok {
+			fmt.Println("Error: choose what to export with --space, --folder or --list.")
+			os.Exit(1)
+		}
+		members := &memberResolver{apiToken: apiToken, teamID: teamID}
+		loc, err := members.location()
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		tasks, failures, err := fetchExport(apiToken, teamID, scope, !exportNoClosed, exportConcurrency)
+		if err != nil {
+			fmt.Println("Error fetching tasks:", err)
+			os.Exit(1)
+		}
+
+		out := exportOut
+		if exportFormat == "md" {
+			if out == "" {
+				out = slugify(scope.name)
+			}
+			if out == "" {
+				out = scope.id
+			}
+			err = writeMarkdownExport(out, tasks, loc)
+		} else {
+			var w io.Writer = os.Stdout
+			var f *os.File
+			if out != "" && out != "-" {
+				f, err = os.Create(out)
+				if err != nil {
+					fmt.Println("Error:", err)
+					os.Exit(1)
+				}
+				w = f
+			}
+			if exportFormat == "csv" {
+				err = writeCSVExport(w, tasks, loc)
+			} else {
+				err = writeJSONExport(w, scope, tasks)
+			}
+			if f != nil {
+				if closeErr := f.Close(); err == nil {
+					err = closeErr
+				}
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error writing the export:", err)
+			os.Exit(1)
+		}
+		if out != "" && out != "-" {
+			fmt.Printf("Exported %d tasks of %s to %s.\n", len(tasks), scope.name, out)
+		}
+		// Failures go to stderr, which CSV and JSON written to stdout don't.
+		if len(failures) > 0 {
+			fmt.Fprintf(os.Stderr, "Left out %d task(s) that could not be loaded:\n", len(failures))
+			printFailures(os.Stderr, failures)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportSpace, "space", "", "space name or ID")
+	exportCmd.Flags().StringVar(&exportFolder, "folder", "", "folder name or ID")
+	exportCmd.Flags().StringVar(&exportList, "list", "", "list name or ID")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "md", "export format: md, csv or json")
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "directory (md) or file (csv, json) to write to")
+	exportCmd.Flags().BoolVar(&exportNoClosed, "no-closed", false, "leave out closed tasks")
+	exportCmd.Flags().IntVar(&exportConcurrency, "concurrency", 4, "number of tasks fetched in parallel")
+}
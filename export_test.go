@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchAllComments(t *testing.T) {
+	// 60 comments, newest first, served 25 at a time after start_id.
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		first := 60
+		if id := r.URL.Query().Get("start_id"); id != "" {
+			first, _ = strconv.Atoi(id)
+			first--
+		}
+		var page []string
+		for id := first; id > 0 && len(page) < 25; id-- {
+			page = append(page, fmt.Sprintf(`{"id":"%d","date":"%d"}`, id, id*1000))
+		}
+		fmt.Fprintf(w, `{"comments":[%s]}`, strings.Join(page, ","))
+	}))
+
+	comments, err := fetchAllComments("pk_1", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 60 || comments[0].ID != "60" || comments[59].ID != "1" {
+		t.Errorf("got %d comments, want 60 from 60 down to 1", len(comments))
+	}
+}
+
+func TestTaskFolder(t *testing.T) {
+	var task Task
+	task.Folder.Name, task.List.Name = "hidden", "Inbox"
+	if taskFolder(task) != "" || taskLocation(task) != "Inbox" {
+		t.Errorf("folderless list: folder %q, location %q", taskFolder(task), taskLocation(task))
+	}
+	task.Folder.Name = "Work"
+	if taskFolder(task) != "Work" || taskLocation(task) != "Work / Inbox" {
+		t.Errorf("folder: folder %q, location %q", taskFolder(task), taskLocation(task))
+	}
+}
+
+func TestFetchExportLeavesOutFailedTasks(t *testing.T) {
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/team/1/task":
+			w.Write([]byte(`{"tasks":[{"id":"a","name":"First"},{"id":"b","name":"Second"},{"id":"c","name":"Third"}],"last_page":true}`))
+		case "/api/v2/task/a", "/api/v2/task/c":
+			fmt.Fprintf(w, `{"id":"%s"}`, strings.TrimPrefix(r.URL.Path, "/api/v2/task/"))
+		case "/api/v2/task/a/comment":
+			w.Write([]byte(`{"comments":[]}`))
+		default:
+			http.Error(w, `{"err":"gone"}`, http.StatusNotFound)
+		}
+	}))
+
+	tasks, failures, err := fetchExport("pk_1", "1", taskScope{kind: scopeList, id: "900"}, true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "a" {
+		t.Errorf("exported %+v, want task a", tasks)
+	}
+	var failed []string
+	for _, f := range failures {
+		failed = append(failed, f.task.Name+": "+f.err.Error())
+	}
+	if len(failed) != 2 || !strings.HasPrefix(failed[0], "Second: ") || !strings.HasPrefix(failed[1], "Third: fetch comments failed") {
+		t.Errorf("failures %q, want Second and the comments of Third", failed)
+	}
+}
+
+func TestCSVExportEscapesFormulas(t *testing.T) {
+	task := exportedTask{Task: Task{ID: "a", Name: "=HYPERLINK(\"https://x.io\")", Content: "- step one"}}
+	task.Tags = []Tag{{Name: "@ops"}}
+	task.Comments = []Comment{{Date: "0", Comment: []struct {
+		Text string `json:"text"`
+	}{{Text: "+1"}}}}
+	var b strings.Builder
+	if err := writeCSVExport(&b, []exportedTask{task}, time.UTC); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := make(map[string]string)
+	for i, name := range rows[0] {
+		row[name] = rows[1][i]
+	}
+	want := map[string]string{
+		"id":          "a",
+		"name":        "'=HYPERLINK(\"https://x.io\")",
+		"tags":        "'@ops",
+		"description": "'- step one",
+		"comments":    "1970-01-01 00:00 : +1",
+	}
+	for name, value := range want {
+		if row[name] != value {
+			t.Errorf("%s = %q, want %q", name, row[name], value)
+		}
+	}
+
+	for _, tt := range []struct{ in, want string }{
+		{"", ""}, {"plain", "plain"}, {"-3", "'-3"}, {"+", "'+"}, {"\tx", "'\tx"}, {"a=b", "a=b"},
+	} {
+		if got := csvCell(tt.in); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/text/unicode/norm"
+)
+
+// --- GIT INTEGRATION ---
+
+// gitSettings configures how tasks are referenced in branches and commits.
+type gitSettings struct {
+	// BranchPattern names new branches; {id} is the task ID and {slug} the
+	// task name in lowercase words separated by dashes.
+	BranchPattern string `yaml:"branch_pattern,omitempty"`
+	// Trailer is the commit message trailer that links a commit to its task.
+	Trailer string `yaml:"trailer,omitempty"`
+}
+
+const (
+	defaultBranchPattern = "CU-{id}-{slug}"
+	defaultTrailer       = "ClickUp"
+	maxSlugLength        = 50
+)
+
+func (g gitSettings) branchPattern() string {
+	if g.BranchPattern == "" {
+		return defaultBranchPattern
+	}
+	return g.BranchPattern
+}
+
+func (g gitSettings) trailer() string {
+	if g.Trailer == "" {
+		return defaultTrailer
+	}
+	return g.Trailer
+}
+
+func (g gitSettings) validate() error {
+	if !strings.Contains(g.branchPattern(), "{id}") {
+		return fmt.Errorf("git.branch_pattern %q must contain {id}", g.BranchPattern)
+	}
+	return nil
+}
+
+// slugLetters spells the Latin letters that have no accent to strip.
+var slugLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+}
+
+// slugify turns a task name into a branch-safe slug, cut at a word boundary.
+// Accented Latin letters are spelled without their accents, and letters of
+// other scripts are kept.
+func slugify(name string) string {
+	const (
+		separator = iota
+		ascii
+		other
+	)
+	var b strings.Builder
+	last := separator
+	for _, r := range norm.NFKD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.M, r):
+			// Accents of Latin letters are dropped; marks of other
+			// scripts belong to their letter.
+			if last == other {
+				b.WriteRune(r)
+			}
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			last = ascii
+		case slugLetters[r] != "":
+			b.WriteString(slugLetters[r])
+			last = ascii
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			last = other
+		default:
+			if last != separator {
+				b.WriteByte('-')
+			}
+			last = separator
+		}
+	}
+	slug := []rune(strings.Trim(norm.NFC.String(b.String()), "-"))
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndex(string(slug), "-"); i > 0 {
+			return string(slug)[:i]
+		}
+	}
+	return string(slug)
+}
+
+// branchName fills in the branch pattern for a task.
+func (g gitSettings) branchName(task Task) string {
+	return strings.NewReplacer("{id}", task.ID, "{slug}", slugify(task.Name)).Replace(g.branchPattern())
+}
+
+// branchTaskID finds the task ID in a branch created from the branch pattern.
+func (g gitSettings) branchTaskID(branch string) (string, bool) {
+	pattern := regexp.QuoteMeta(g.branchPattern())
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{id}"), `([0-9a-zA-Z]+)`, 1)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("{id}"), `[0-9a-zA-Z]+`)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("{slug}"), `[\p{Ll}\p{Lo}\p{Lm}\p{M}\p{N}-]*`)
+	match := regexp.MustCompile(`(?:^|/)` + pattern + `$`).FindStringSubmatch(branch)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// git runs a git command and returns its trimmed output.
+func git(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// currentBranchTaskID returns the task ID of the checked out branch.
+func currentBranchTaskID() (string, string, error) {
+	branch, err := git("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", "", fmt.Errorf("no branch checked out: %v", err)
+	}
+	id, ok := settings.Git.branchTaskID(branch)
+	if !ok {
+		return branch, "", fmt.Errorf("branch %s does not match the pattern %s", branch, settings.Git.branchPattern())
+	}
+	return branch, id, nil
+}
+
+// --- GIT (CLI) ---
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Link git branches and commits to tasks",
+}
+
+var gitBranchCmd = &cobra.Command{
+	Use:   "branch <task>",
+	Short: "Create and check out a branch for a task",
+	Long: `Create and check out a branch for a task, named after git.branch_pattern
+in the config file (default "CU-{id}-{slug}"). An existing branch is checked out.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		var task Task
+		switch msg := fetchTaskRefCmd(apiToken, teamID, args[0])().(type) {
+		case error:
+			fmt.Println("Error fetching task:", msg)
+			os.Exit(1)
+		case Task:
+			task = msg
+		}
+		branch := settings.Git.branchName(task)
+		if _, err := git("rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+			if _, err := git("checkout", branch); err != nil {
+				fmt.Println("Error checking out branch:", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Switched to existing branch %s.\n", branch)
+			return
+		}
+		if _, err := git("checkout", "-b", branch); err != nil {
+			fmt.Println("Error creating branch:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Switched to new branch %s.\n", branch)
+	},
+}
+
+var gitCommitMsgCmd = &cobra.Command{
+	Use:   "commit-msg <file> [source] [sha]",
+	Short: "prepare-commit-msg hook that adds the branch's task to the message",
+	Long: `Add a trailer such as "ClickUp: CU-86abc123" with the task of the current
+branch to a commit message. Meant to be called from a prepare-commit-msg hook;
+install one with --install. Merge and squash messages, branches that don't
+match git.branch_pattern and messages that already mention the task are left
+alone. Errors are printed but never stop the commit.`,
+	Args: cobra.RangeArgs(0, 3),
+	// A hook that fails aborts the commit, so a broken config file only
+	// means the default branch pattern and trailer are used.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cfg, err := readConfig()
+		if err == nil {
+			err = cfg.Git.validate()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clup: ignoring the config file:", err)
+			return
+		}
+		settings.Git = cfg.Git
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if gitInstallHook {
+			installCommitMsgHook()
+			return
+		}
+		if len(args) == 0 {
+			fmt.Println("Error: the commit message file is required.")
+			os.Exit(1)
+		}
+		if len(args) > 1 && (args[1] == "merge" || args[1] == "squash") {
+			return
+		}
+		_, id, err := currentBranchTaskID()
+		if err != nil {
+			return
+		}
+		message, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clup: error reading commit message:", err)
+			return
+		}
+		ref := "CU-" + id
+		if bytes.Contains(message, []byte(ref)) {
+			return
+		}
+		trailer := fmt.Sprintf("%s: %s", settings.Git.trailer(), ref)
+		if _, err := git("interpret-trailers", "--in-place", "--trailer", trailer, args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, "clup: error adding trailer:", err)
+		}
+	},
+}
+
+var gitCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the task of the current branch",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, id, err := currentBranchTaskID()
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		runTUIAt(id, actionView)
+	},
+}
+
+var gitInstallHook bool
+
+const commitMsgHook = `#!/bin/sh
+# Added by clup: links commits to the task of the current branch.
+exec clup git commit-msg "$@"
+`
+
+// installCommitMsgHook writes a prepare-commit-msg hook into the current
+// repository, refusing to replace a hook that clup did not write.
+func installCommitMsgHook() {
+	hooksDir, err := git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		fmt.Println("Error finding the hooks directory:", err)
+		os.Exit(1)
+	}
+	path := filepath.Join(hooksDir, "prepare-commit-msg")
+	if existing, err := os.ReadFile(path); err == nil && !bytes.Contains(existing, []byte("clup git commit-msg")) {
+		fmt.Printf("Error: %s already exists; add `clup git commit-msg \"$@\"` to it by hand.\n", path)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		fmt.Println("Error installing hook:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, []byte(commitMsgHook), 0755); err != nil {
+		fmt.Println("Error installing hook:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Installed %s.\n", path)
+}
+
+func init() {
+	gitCommitMsgCmd.Flags().BoolVar(&gitInstallHook, "install", false, "install the prepare-commit-msg hook in the current repository")
+	gitCmd.AddCommand(gitBranchCmd, gitCommitMsgCmd, gitCurrentCmd)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Fix login on Safari", "fix-login-on-safari"},
+		{"  [API] Rate limits: 429s!  ", "api-rate-limits-429s"},
+		{"Ünïcode straße", "unicode-strasse"},
+		{"Café crème", "cafe-creme"},
+		{"Łódź ﬁx", "lodz-fix"},
+		{"Привет, мир", "привет-мир"},
+		{"修复登录", "修复登录"},
+		{"हिन्दी text", "हिन्दी-text"},
+		{"---", ""},
+		{"Make the export of every space folder and list work with very long names", "make-the-export-of-every-space-folder-and-list"},
+		{"abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabc", "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwx"},
+		{strings.Repeat("ü", 60), strings.Repeat("u", 50)},
+		{strings.Repeat("й", 60), strings.Repeat("й", 50)},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.in); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBranchTaskID(t *testing.T) {
+	tests := []struct {
+		pattern string
+		branch  string
+		want    string
+		ok      bool
+	}{
+		{"", "CU-86abc123-fix-login", "86abc123", true},
+		{"", "feature/CU-86abc123-fix-login", "86abc123", true},
+		{"", "main", "", false},
+		{"", "CU-86abc123-Fix_Login", "", false},
+		{"", "CU-86abc123-привет-мир", "86abc123", true},
+		{"{id}/{slug}", "86abc123/fix-login", "86abc123", true},
+		{"{id}/{slug}", "fix-login", "", false},
+		{"task.{id}", "task.86abc123", "86abc123", true},
+		{"task.{id}", "taskX86abc123", "", false},
+	}
+	for _, tt := range tests {
+		g := gitSettings{BranchPattern: tt.pattern}
+		got, ok := g.branchTaskID(tt.branch)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("branchTaskID(%q) with %q = %q, %v, want %q, %v", tt.branch, g.branchPattern(), got, ok, tt.want, tt.ok)
+		}
+		if tt.ok {
+			if id, _ := g.branchTaskID(g.branchName(Task{ID: tt.want, Name: "Fix login"})); id != tt.want {
+				t.Errorf("branchTaskID(branchName) with %q = %q, want %q", g.branchPattern(), id, tt.want)
+			}
+		}
+	}
+}
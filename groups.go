@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// --- GROUPING ---
+
+// taskOrder is how a task list is sorted and grouped. Changes made in the
+// task list are saved per space in the config file.
+type taskOrder struct {
+	Sort  string `yaml:"sort,omitempty"`
+	Group string `yaml:"group,omitempty"`
+}
+
+// noOrder keeps the API order, or turns grouping off, for a space whose
+// defaults would sort or group it.
+const noOrder = "none"
+
+// sortCycle is the order in which the sort key cycles through the sort orders.
+var sortCycle = []string{noOrder, "priority", "due", "status", "updated", "created", "name"}
+
+// groupCycle is the order in which the group key cycles through the groupings.
+var groupCycle = []string{noOrder, "list", "status", "assignee", "priority", "due"}
+
+func (o taskOrder) validate() error {
+	if o.Sort != "" && o.Sort != noOrder {
+		if _, ok := taskSorters[strings.TrimPrefix(o.Sort, "-")]; !ok {
+			return fmt.Errorf("unknown sort order %q, expected one of %s", o.Sort, strings.Join(sortKeys(), ", "))
+		}
+	}
+	if o.Group != "" && o.Group != noOrder {
+		if _, ok := taskGroupers[o.Group]; !ok {
+			return fmt.Errorf("unknown grouping %q, expected one of %s", o.Group, strings.Join(groupCycle[1:], ", "))
+		}
+	}
+	return nil
+}
+
+// orderFor returns the order saved for a space, or the configured defaults.
+func (c *config) orderFor(spaceID string) taskOrder {
+	order := taskOrder{Sort: c.Sort, Group: c.Group}
+	if saved, ok := c.Spaces[spaceID]; ok {
+		if saved.Sort != "" {
+			order.Sort = saved.Sort
+		}
+		if saved.Group != "" {
+			order.Group = saved.Group
+		}
+	}
+	return order
+}
+
+// saveOrder stores the order of a space in the config file, keeping the rest
+// of the file as it is.
+func saveOrder(spaceID string, order taskOrder) error {
+	root, err := readConfigNode()
+	if err != nil {
+		return err
+	}
+	for _, field := range []struct{ name, value string }{{"sort", order.Sort}, {"group", order.Group}} {
+		value := field.value
+		if value == "" {
+			value = noOrder
+		}
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+		if err := setNode(root, []string{"spaces", spaceID, field.name}, node); err != nil {
+			return err
+		}
+	}
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	if err := writeConfigFile(data); err != nil {
+		return err
+	}
+	if settings.Spaces == nil {
+		settings.Spaces = make(map[string]taskOrder)
+	}
+	settings.Spaces[spaceID] = order
+	return nil
+}
+
+// nextIn returns the entry after current in cycle.
+func nextIn(cycle []string, current string) string {
+	if current == "" {
+		current = noOrder
+	}
+	for i, name := range cycle {
+		if name == current {
+			return cycle[(i+1)%len(cycle)]
+		}
+	}
+	return cycle[0]
+}
+
+// nextSort moves on to the next sort order, keeping its direction.
+func (o taskOrder) nextSort() taskOrder {
+	reversed := strings.HasPrefix(o.Sort, "-")
+	o.Sort = nextIn(sortCycle, strings.TrimPrefix(o.Sort, "-"))
+	if reversed && o.Sort != noOrder {
+		o.Sort = "-" + o.Sort
+	}
+	return o
+}
+
+func (o taskOrder) reverseSort() taskOrder {
+	switch {
+	case o.Sort == "" || o.Sort == noOrder:
+	case strings.HasPrefix(o.Sort, "-"):
+		o.Sort = o.Sort[1:]
+	default:
+		o.Sort = "-" + o.Sort
+	}
+	return o
+}
+
+func (o taskOrder) nextGroup() taskOrder {
+	o.Group = nextIn(groupCycle, o.Group)
+	return o
+}
+
+// String describes the order for the status bar.
+func (o taskOrder) String() string {
+	s := "unsorted"
+	if o.Sort != "" && o.Sort != noOrder {
+		s = "sorted by " + strings.TrimPrefix(o.Sort, "-")
+		if strings.HasPrefix(o.Sort, "-") {
+			s += " (reversed)"
+		}
+	}
+	if o.Group != "" && o.Group != noOrder {
+		s += ", grouped by " + o.Group
+	}
+	return s
+}
+
+// taskGroup is a section of a grouped task list. Groups are shown by rank and
+// then by name.
+type taskGroup struct {
+	name string
+	rank int
+}
+
+// taskGroupers return the groups a task belongs to for each grouping, with
+// due dates grouped relative to now. A task with several assignees is shown
+// under each of them.
+var taskGroupers = map[string]func(t Task, now time.Time) []taskGroup{
+	"list": func(t Task, _ time.Time) []taskGroup {
+		return []taskGroup{{name: taskLocation(t)}}
+	},
+	"status": func(t Task, _ time.Time) []taskGroup {
+		return []taskGroup{{name: t.Status.Status, rank: t.Status.Order}}
+	},
+	"assignee": func(t Task, _ time.Time) []taskGroup {
+		if len(t.Assignees) == 0 {
+			return []taskGroup{{name: "Unassigned", rank: 1}}
+		}
+		groups := make([]taskGroup, len(t.Assignees))
+		for i, a := range t.Assignees {
+			groups[i] = taskGroup{name: a.Username}
+		}
+		return groups
+	},
+	"priority": func(t Task, _ time.Time) []taskGroup {
+		p := taskPriorityValue(t)
+		if p == 0 {
+			return []taskGroup{{name: "No priority", rank: 5}}
+		}
+		return []taskGroup{{name: priorityName(p), rank: p}}
+	},
+	"due": func(t Task, now time.Time) []taskGroup {
+		return []taskGroup{dueGroup(t, now)}
+	},
+}
+
+// taskGroupHeader starts a section of a grouped task list. It can't be
+// selected; the cursor skips over it.
+type taskGroupHeader struct {
+	name  string
+	count int
+}
+
+func (h taskGroupHeader) FilterValue() string { return "" }
+
+func (h taskGroupHeader) render(w io.Writer, width int) {
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.Accent))
+	label := fmt.Sprintf("%s %s", style.Render(h.name), blurredStyle.Render(fmt.Sprintf("(%d)", h.count)))
+	rule := width - lipgloss.Width(label) - 4
+	fmt.Fprint(w, blurredStyle.Render("── ")+label+" "+blurredStyle.Render(strings.Repeat("─", max(rule, 0))))
+}
+
+// taskItems sorts and groups tasks for display in the task list. Due dates
+// are grouped relative to now, in its time zone.
+func taskItems(tasks []Task, order taskOrder, now time.Time) []list.Item {
+	sorted := append([]Task(nil), tasks...)
+	sortTasks(sorted, order.Sort)
+	grouper, ok := taskGroupers[order.Group]
+	if !ok {
+		items := make([]list.Item, len(sorted))
+		for i, task := range sorted {
+			items[i] = task
+		}
+		return items
+	}
+
+	type section struct {
+		taskGroup
+		tasks []Task
+	}
+	sections := make(map[string]*section)
+	var ordered []*section
+	for _, task := range sorted {
+		for _, g := range grouper(task, now) {
+			s, ok := sections[g.name]
+			if !ok {
+				s = &section{taskGroup: g}
+				sections[g.name] = s
+				ordered = append(ordered, s)
+			}
+			s.tasks = append(s.tasks, task)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].rank != ordered[j].rank {
+			return ordered[i].rank < ordered[j].rank
+		}
+		return strings.ToLower(ordered[i].name) < strings.ToLower(ordered[j].name)
+	})
+	var items []list.Item
+	for _, s := range ordered {
+		items = append(items, taskGroupHeader{name: s.name, count: len(s.tasks)})
+		for _, task := range s.tasks {
+			items = append(items, task)
+		}
+	}
+	return items
+}
+
+// skipGroupHeader moves the cursor off a group header, in the direction it
+// was moving from previous.
+func (m *model) skipGroupHeader(previous int) {
+	if _, ok := m.list.SelectedItem().(taskGroupHeader); !ok {
+		return
+	}
+	last := len(m.list.VisibleItems()) - 1
+	up := m.list.Index() < previous
+	if (up && m.list.Index() == 0) || (!up && m.list.Index() == last) {
+		up = !up
+	}
+	if up {
+		m.list.CursorUp()
+	} else {
+		m.list.CursorDown()
+	}
+}
+
+// showTasks puts the loaded tasks into the task list in the current order,
+// or in the order of the agenda.
+func (m *model) showTasks() {
+	var tasks []Task
+	for _, task := range m.tasks {
+		if m.shows(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	order := m.order
+	if m.agenda {
+		order = agendaOrder
+	}
+	m.list.SetItems(taskItems(tasks, order, m.datesNow()))
+	m.skipGroupHeader(-1)
+}
+
+// shows reports whether a task is left in the task list by the tag filter,
+// the agenda, which leaves out tasks without a due date, and the day picked
+// in the calendar.
+func (m model) shows(task Task) bool {
+	switch {
+	case len(m.tagFilter) > 0 && !hasAnyTag(task, m.tagFilter):
+		return false
+	case m.agenda && task.DueDate == "":
+		return false
+	case !m.calendarDay.IsZero() && !dueOn(task, m.calendarDay):
+		return false
+	}
+	return true
+}
+
+// setOrder re-sorts the task list and saves the order for the space, or
+// for the view being shown. The agenda is left, as it has its own order.
+func (m model) setOrder(order taskOrder) (tea.Model, tea.Cmd) {
+	m.order = order
+	if m.agenda {
+		m.agenda = false
+		m.list.Title = m.taskListTitle()
+	}
+	m.showTasks()
+	status := statusMessageStyle("Tasks " + order.String())
+	var err error
+	if m.scope.kind == scopeView {
+		view := settings.Views[m.scope.id]
+		view.Sort, view.Group = order.Sort, order.Group
+		err = saveView(m.scope.id, view)
+	} else {
+		err = saveOrder(m.spaceID, order)
+	}
+	if err != nil {
+		status = failureStyle.Render("Could not save the order: " + err.Error())
+	}
+	return m, m.list.NewStatusMessage(status)
+}
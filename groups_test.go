@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// itemNames shows task list items as task IDs and "# group (count)" headers.
+func itemNames(items []list.Item) []string {
+	var names []string
+	for _, item := range items {
+		switch item := item.(type) {
+		case taskGroupHeader:
+			names = append(names, fmt.Sprintf("# %s (%d)", item.name, item.count))
+		case Task:
+			names = append(names, item.ID)
+		}
+	}
+	return names
+}
+
+func TestTaskItems(t *testing.T) {
+	task := func(id, status string, order int, priority string, assignees ...string) Task {
+		t := Task{ID: id}
+		t.Status.Status, t.Status.Order = status, order
+		if priority != "" {
+			t.Priority = &TaskPriority{ID: priority}
+		}
+		for _, a := range assignees {
+			t.Assignees = append(t.Assignees, Member{Username: a})
+		}
+		return t
+	}
+	tasks := []Task{
+		task("1", "review", 2, "3", "bob"),
+		task("2", "open", 0, "", "ann", "bob"),
+		task("3", "review", 2, "1"),
+		task("4", "in progress", 1, "2", "Carl"),
+	}
+	tests := []struct {
+		order taskOrder
+		want  []string
+	}{
+		{taskOrder{}, []string{"1", "2", "3", "4"}},
+		{taskOrder{Sort: "priority"}, []string{"3", "4", "1", "2"}},
+		{taskOrder{Sort: "-priority"}, []string{"2", "1", "4", "3"}},
+		{
+			taskOrder{Group: "status"},
+			[]string{"# open (1)", "2", "# in progress (1)", "4", "# review (2)", "1", "3"},
+		},
+		{
+			taskOrder{Sort: "priority", Group: "status"},
+			[]string{"# open (1)", "2", "# in progress (1)", "4", "# review (2)", "3", "1"},
+		},
+		{
+			taskOrder{Group: "assignee"},
+			[]string{"# ann (1)", "2", "# bob (2)", "1", "2", "# Carl (1)", "4", "# Unassigned (1)", "3"},
+		},
+		{
+			taskOrder{Group: "priority"},
+			[]string{"# Urgent (1)", "3", "# High (1)", "4", "# Normal (1)", "1", "# No priority (1)", "2"},
+		},
+	}
+	for _, tt := range tests {
+		if got := itemNames(taskItems(tasks, tt.order, time.Now())); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("taskItems(%+v) = %q, want %q", tt.order, got, tt.want)
+		}
+	}
+	if tasks[0].ID != "1" || tasks[3].ID != "4" {
+		t.Errorf("taskItems reordered its argument")
+	}
+}
@@ -0,0 +1,520 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// --- WORKSPACE HIERARCHY ---
+
+// The space selection is a tree of spaces, their folders and lists. The
+// folders and lists of a space are loaded when it is first expanded, and
+// selecting any node shows the tasks within it.
+
+type scopeKind int
+
+const (
+	scopeSpace scopeKind = iota
+	scopeFolder
+	scopeList
+	scopeView
+)
+
+func (k scopeKind) String() string {
+	switch k {
+	case scopeFolder:
+		return "folder"
+	case scopeList:
+		return "list"
+	case scopeView:
+		return "view"
+	}
+	return "space"
+}
+
+// taskScope is the space, folder, list or saved view the task list shows.
+type taskScope struct {
+	kind scopeKind
+	id   string
+	name string
+}
+
+// queryParam is the parameter of the team task endpoint that filters by the
+// scope; ClickUp calls folders projects.
+func (s taskScope) queryParam() string {
+	switch s.kind {
+	case scopeFolder:
+		return "project_ids[]"
+	case scopeList:
+		return "list_ids[]"
+	}
+	return "space_ids[]"
+}
+
+// queryTerm is the scope as a query term for queryTasks.
+func (s taskScope) queryTerm() string {
+	switch s.kind {
+	case scopeFolder:
+		return "folder=" + s.id
+	case scopeList:
+		return "list=" + s.id
+	}
+	return "space=" + s.id
+}
+
+// where is the query for the tasks of the scope: the filter of a view, or the
+// default filter narrowed to the space, folder or list.
+func (s taskScope) where() string {
+	if s.kind == scopeView {
+		return settings.Views[s.id].Filter
+	}
+	return strings.TrimSpace(settings.Filter + " " + s.queryTerm())
+}
+
+func (s taskScope) query() neturl.Values {
+	return neturl.Values{s.queryParam(): {s.id}}
+}
+
+// taskCount is the number of tasks in a folder or list, which ClickUp sends
+// as a number, a string or null.
+type taskCount int
+
+func (c *taskCount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid task count %s", data)
+	}
+	*c = taskCount(n)
+	return nil
+}
+
+// count adds up the tasks in the lists of a folder.
+func (f Folder) count() int {
+	n := 0
+	for _, l := range f.Lists {
+		n += int(l.TaskCount)
+	}
+	return n
+}
+
+// hierarchy holds the spaces of the workspace and the folders and lists of
+// the spaces loaded so far.
+type hierarchy struct {
+	spaces   []Space
+	folders  map[string][]Folder
+	lists    map[string][]ListInfo
+	loading  map[string]bool
+	expanded map[string]bool
+}
+
+func newHierarchy() hierarchy {
+	return hierarchy{
+		folders:  make(map[string][]Folder),
+		lists:    make(map[string][]ListInfo),
+		loading:  make(map[string]bool),
+		expanded: make(map[string]bool),
+	}
+}
+
+func (h hierarchy) loaded(spaceID string) bool {
+	_, ok := h.lists[spaceID]
+	return ok
+}
+
+func (h hierarchy) spaceName(spaceID string) string {
+	for _, s := range h.spaces {
+		if s.ID == spaceID {
+			return s.Name
+		}
+	}
+	return spaceID
+}
+
+// treeNode is a row of the tree.
+type treeNode struct {
+	scope      taskScope
+	spaceID    string
+	parent     taskScope
+	depth      int
+	count      int
+	expandable bool
+	expanded   bool
+	loading    bool
+}
+
+func (n treeNode) FilterValue() string { return n.scope.name }
+
+func (n treeNode) key() string { return nodeKey(n.scope.kind, n.scope.id) }
+
+// container is where a new list goes when n is selected: the space or folder
+// itself, or the parent of a list.
+func (n treeNode) container() taskScope {
+	if n.scope.kind == scopeList {
+		return n.parent
+	}
+	return n.scope
+}
+
+func nodeKey(kind scopeKind, id string) string {
+	return fmt.Sprintf("%d:%s", kind, id)
+}
+
+// items returns the visible rows of the tree.
+func (h hierarchy) items() []list.Item {
+	var items []list.Item
+	for _, s := range h.spaces {
+		space := treeNode{
+			scope:      taskScope{kind: scopeSpace, id: s.ID, name: s.Name},
+			spaceID:    s.ID,
+			count:      -1,
+			expandable: true,
+			expanded:   h.expanded[nodeKey(scopeSpace, s.ID)],
+			loading:    h.loading[s.ID],
+		}
+		if !h.loaded(s.ID) {
+			items = append(items, space)
+			continue
+		}
+		space.count = 0
+		var children []list.Item
+		for _, f := range h.folders[s.ID] {
+			folder := treeNode{
+				scope:      taskScope{kind: scopeFolder, id: f.ID, name: f.Name},
+				spaceID:    s.ID,
+				parent:     space.scope,
+				depth:      1,
+				count:      f.count(),
+				expandable: len(f.Lists) > 0,
+				expanded:   h.expanded[nodeKey(scopeFolder, f.ID)],
+			}
+			space.count += folder.count
+			children = append(children, folder)
+			if !folder.expanded {
+				continue
+			}
+			for _, l := range f.Lists {
+				children = append(children, listNode(l, s.ID, folder.scope, 2))
+			}
+		}
+		for _, l := range h.lists[s.ID] {
+			space.count += int(l.TaskCount)
+			children = append(children, listNode(l, s.ID, space.scope, 1))
+		}
+		items = append(items, space)
+		if space.expanded {
+			items = append(items, children...)
+		}
+	}
+	return items
+}
+
+func listNode(l ListInfo, spaceID string, parent taskScope, depth int) treeNode {
+	return treeNode{
+		scope:   taskScope{kind: scopeList, id: l.ID, name: l.Name},
+		spaceID: spaceID,
+		parent:  parent,
+		depth:   depth,
+		count:   int(l.TaskCount),
+	}
+}
+
+type treeDelegate struct{}
+
+func (d treeDelegate) Height() int                               { return 1 }
+func (d treeDelegate) Spacing() int                              { return 0 }
+func (d treeDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+func (d treeDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	n, ok := listItem.(treeNode)
+	if !ok {
+		return
+	}
+	marker := " "
+	switch {
+	case n.loading:
+		marker = "…"
+	case n.expanded:
+		marker = "▾"
+	case n.expandable:
+		marker = "▸"
+	}
+	line := strings.Repeat("  ", n.depth) + marker + " " + n.scope.name
+	count := ""
+	if n.count >= 0 {
+		count = blurredStyle.Render(fmt.Sprintf(" (%d)", n.count))
+	}
+	if index == m.Index() {
+		fmt.Fprint(w, focusedStyle.Render("> "+line)+count)
+	} else {
+		fmt.Fprint(w, "  "+line+count)
+	}
+}
+
+// newSpaceTree creates the list the tree is shown in.
+func newSpaceTree(title string, width, height int) list.Model {
+	l := newList([]list.Item{}, treeDelegate{}, width, height)
+	l.Title = title
+	// The arrow keys expand and collapse nodes instead of paging.
+	l.KeyMap.NextPage.SetKeys("pgdown", "f")
+	l.KeyMap.PrevPage.SetKeys("pgup", "b")
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Expand, keys.Collapse}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			keys.Expand, keys.Collapse, keys.NewList, keys.NewFolder, keys.ListTemplate,
+			keys.Rename, keys.Archive, keys.Delete, keys.SwitchWorkspace,
+		}
+	}
+	return l
+}
+
+// spaceTreeMsg carries the folders and folderless lists of a space.
+type spaceTreeMsg struct {
+	spaceID string
+	folders []Folder
+	lists   []ListInfo
+}
+
+func fetchSpaceTreeCmd(apiToken, spaceID string) tea.Cmd {
+	return func() tea.Msg {
+		tree, err := fetchSpaceTree(apiToken, spaceID)
+		if err != nil {
+			return err
+		}
+		return tree
+	}
+}
+
+// fetchSpaceTree loads the folders and folderless lists of a space.
+func fetchSpaceTree(apiToken, spaceID string) (spaceTreeMsg, error) {
+	tree := spaceTreeMsg{spaceID: spaceID}
+	switch msg := fetchFoldersWithListsCmd(apiToken, spaceID)().(type) {
+	case error:
+		return tree, msg
+	case FoldersResponse:
+		tree.folders = msg.Folders
+	}
+	switch msg := fetchFolderlessListsCmd(apiToken, spaceID)().(type) {
+	case error:
+		return tree, msg
+	case ListsResponse:
+		tree.lists = msg.Lists
+	}
+	if tree.lists == nil {
+		tree.lists = []ListInfo{}
+	}
+	return tree, nil
+}
+
+// setTreeItems shows the tree, keeping the selected node selected.
+func (m *model) setTreeItems() {
+	selected, _ := m.spaceList.SelectedItem().(treeNode)
+	items := m.tree.items()
+	m.spaceList.SetItems(items)
+	m.selectTreeNode(items, selected.key())
+}
+
+func (m *model) selectTreeNode(items []list.Item, k string) {
+	for i, item := range items {
+		if item.(treeNode).key() == k {
+			m.spaceList.Select(i)
+			return
+		}
+	}
+}
+
+// expandTreeNode expands a space or folder, loading the space first.
+func (m model) expandTreeNode(n treeNode) (model, tea.Cmd) {
+	if !n.expandable {
+		return m, nil
+	}
+	m.tree.expanded[n.key()] = true
+	if n.scope.kind == scopeSpace && !m.tree.loaded(n.spaceID) {
+		if m.tree.loading[n.spaceID] {
+			return m, nil
+		}
+		m.tree.loading[n.spaceID] = true
+		m.setTreeItems()
+		return m, fetchSpaceTreeCmd(m.apiToken, n.spaceID)
+	}
+	m.setTreeItems()
+	return m, nil
+}
+
+// collapseTreeNode collapses an expanded node, or moves to the parent of one
+// that is not.
+func (m model) collapseTreeNode(n treeNode) model {
+	if n.expanded {
+		delete(m.tree.expanded, n.key())
+		m.setTreeItems()
+		return m
+	}
+	m.selectTreeNode(m.spaceList.Items(), nodeKey(n.parent.kind, n.parent.id))
+	return m
+}
+
+// openTreeNode opens the tasks of a node. While creating a task, a list
+// is picked directly and a space leads to the list selection.
+func (m model) openTreeNode(n treeNode) (tea.Model, tea.Cmd) {
+	if m.isCreatingTask {
+		switch n.scope.kind {
+		case scopeSpace:
+			return m.selectSpace(Space{ID: n.spaceID, Name: n.scope.name})
+		case scopeList:
+			m.spaceID = n.spaceID
+			m.scope = n.scope
+			return m.selectList(ListInfo{ID: n.scope.id, Name: n.scope.name})
+		}
+		if n.expanded {
+			return m.collapseTreeNode(n), nil
+		}
+		return m.expandTreeNode(n)
+	}
+	m.spaceID = n.spaceID
+	return m.startTaskList(n.scope)
+}
+
+// --- HIERARCHY (CLI) ---
+
+var (
+	hierarchySpace  string
+	hierarchyFolder string
+)
+
+var spacesCmd = &cobra.Command{
+	Use:   "spaces",
+	Short: "List the spaces of the workspace",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		spaces := fetchSpaces(apiToken, teamID)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME")
+		for _, s := range spaces {
+			fmt.Fprintf(w, "%s\t%s\n", s.ID, s.Name)
+		}
+		w.Flush()
+	},
+}
+
+var foldersCmd = &cobra.Command{
+	Use:   "folders",
+	Short: "List the folders of a space",
+	Long: `List the folders of a space with the number of lists and tasks in them.
+Without --space, the default space from the config file is used, or every
+space when there is none.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		spaces := spacesFor(apiToken, teamID, hierarchySpace)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSPACE\tNAME\tLISTS\tTASKS")
+		for _, s := range spaces {
+			tree := mustFetchSpaceTree(apiToken, s.ID)
+			for _, f := range tree.folders {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\n", f.ID, s.Name, f.Name, len(f.Lists), f.count())
+			}
+		}
+		w.Flush()
+	},
+}
+
+var listsCmd = &cobra.Command{
+	Use:   "lists",
+	Short: "List the lists of a space or folder",
+	Long: `List the lists of a space, both in folders and outside of them, with the
+number of tasks in them. Without --space, the default space from the config
+file is used, or every space when there is none. --folder shows the lists of
+one folder, given by name or ID.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		spaces := spacesFor(apiToken, teamID, hierarchySpace)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSPACE\tFOLDER\tNAME\tTASKS")
+		found := hierarchyFolder == ""
+		for _, s := range spaces {
+			tree := mustFetchSpaceTree(apiToken, s.ID)
+			if hierarchyFolder == "" {
+				for _, l := range tree.lists {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", l.ID, s.Name, "-", l.Name, l.TaskCount)
+				}
+			}
+			for _, f := range tree.folders {
+				if hierarchyFolder != "" && f.ID != hierarchyFolder && !strings.EqualFold(f.Name, hierarchyFolder) {
+					continue
+				}
+				found = true
+				for _, l := range f.Lists {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", l.ID, s.Name, f.Name, l.Name, l.TaskCount)
+				}
+			}
+		}
+		w.Flush()
+		if !found {
+			fmt.Printf("Error: folder %q not found.\n", hierarchyFolder)
+			os.Exit(1)
+		}
+	},
+}
+
+func fetchSpaces(apiToken, teamID string) []Space {
+	switch msg := fetchSpacesCmd(apiToken, teamID)().(type) {
+	case error:
+		fmt.Println("Error fetching spaces:", msg)
+		os.Exit(1)
+	case SpacesResponse:
+		return msg.Spaces
+	}
+	return nil
+}
+
+// spacesFor returns the space given by name or ID, the default space, or all
+// spaces when neither is set.
+func spacesFor(apiToken, teamID, name string) []Space {
+	if name == "" {
+		name = settings.DefaultSpace
+	}
+	spaces := fetchSpaces(apiToken, teamID)
+	if name == "" {
+		return spaces
+	}
+	for _, s := range spaces {
+		if s.ID == name || strings.EqualFold(s.Name, name) {
+			return []Space{s}
+		}
+	}
+	fmt.Printf("Error: space %q not found.\n", name)
+	os.Exit(1)
+	return nil
+}
+
+func mustFetchSpaceTree(apiToken, spaceID string) spaceTreeMsg {
+	tree, err := fetchSpaceTree(apiToken, spaceID)
+	if err != nil {
+		fmt.Println("Error fetching folders and lists:", err)
+		os.Exit(1)
+	}
+	return tree
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{foldersCmd, listsCmd} {
+		cmd.PersistentFlags().StringVar(&hierarchySpace, "space", "", "space name or ID (defaults to default_space)")
+	}
+	listsCmd.PersistentFlags().StringVar(&hierarchyFolder, "folder", "", "folder of the lists (name or ID)")
+}
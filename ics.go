@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// --- ICALENDAR ---
+
+// Tasks with a due date are written as iCalendar events or to-dos, so that
+// calendar apps can show them. UIDs are made from the task IDs, so a task
+// stays the same entry when it is exported again or the feed is refreshed.
+// Dates without a time of day become all-day entries.
+
+// icsTimeLayout is a UTC date-time in iCalendar.
+const icsTimeLayout = "20060102T150405Z"
+
+// icsWriter writes iCalendar content lines, ending them with CRLF and folding
+// them at 75 octets.
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *icsWriter) line(name, value string) {
+	if w.err != nil {
+		return
+	}
+	line := name + ":" + value
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	b.WriteString("\r\n")
+	_, w.err = io.WriteString(w.w, b.String())
+}
+
+// icsText escapes a TEXT value.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsDate writes a date property as a date or as a UTC date-time.
+func (w *icsWriter) date(name string, t time.Time, allDay bool) {
+	if allDay {
+		w.line(name+";VALUE=DATE", t.Format("20060102"))
+		return
+	}
+	w.line(name, t.UTC().Format(icsTimeLayout))
+}
+
+// icsPriority maps ClickUp priorities to iCalendar ones, where 1 is the
+// highest and 9 the lowest.
+var icsPriority = map[int]string{1: "1", 2: "3", 3: "5", 4: "9"}
+
+// icsStatus is the to-do status of a task.
+func icsStatus(task Task) string {
+	switch task.Status.Type {
+	case "closed", "done":
+		return "COMPLETED"
+	case "open":
+		return "NEEDS-ACTION"
+	}
+	return "IN-PROCESS"
+}
+
+// writeICS writes a calendar with a VEVENT or, for todos, a VTODO for every
+// task with a due date. Dates are read in loc.
+func writeICS(out io.Writer, name string, tasks []Task, loc *time.Location, todos bool) error {
+	w := &icsWriter{w: out}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//clup//ClickUp tasks//EN")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", icsText(name))
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", "PT15M")
+	w.line("X-PUBLISHED-TTL", "PT15M")
+	now := time.Now()
+	for _, task := range tasks {
+		due := taskDateOf(task.DueDate, loc)
+		if due.isZero() {
+			continue
+		}
+		start := taskDateOf(task.StartDate, loc)
+		if !start.isZero() && start.time.After(due.time) {
+			start = taskDate{}
+		}
+		// Start and end have to be of the same type, so a date without a
+		// time makes both all-day.
+		allDay := !due.hasTime || (!start.isZero() && !start.hasTime)
+
+		component := "VEVENT"
+		if todos {
+			component = "VTODO"
+		}
+		w.line("BEGIN", component)
+		w.line("UID", task.ID+"@clickup.com")
+		// The stamp comes from the task, so that the output only changes
+		// when the task does.
+		stamp := now
+		if created, ok := msTime(task.DateCreated); ok {
+			stamp = created
+		}
+		if updated, ok := msTime(task.DateUpdated); ok {
+			stamp = updated
+			w.line("LAST-MODIFIED", updated.UTC().Format(icsTimeLayout))
+		}
+		w.line("DTSTAMP", stamp.UTC().Format(icsTimeLayout))
+		w.line("SUMMARY", icsText(task.Name))
+		switch {
+		case todos:
+			if !start.isZero() {
+				w.date("DTSTART", start.time, allDay)
+			}
+			w.date("DUE", due.time, allDay)
+			w.line("STATUS", icsStatus(task))
+		case start.isZero() && allDay:
+			w.date("DTSTART", due.time, true)
+			w.date("DTEND", due.time.AddDate(0, 0, 1), true)
+		case start.isZero():
+			w.date("DTSTART", due.time, false)
+		case allDay:
+			w.date("DTSTART", start.time, true)
+			w.date("DTEND", due.time.AddDate(0, 0, 1), true)
+		default:
+			w.date("DTSTART", start.time, false)
+			w.date("DTEND", due.time, false)
+		}
+		description := "Status: " + task.Status.Status
+		if location := taskLocation(task); location != "" {
+			description += "\nList: " + location
+		}
+		if task.URL != "" {
+			description += "\n" + task.URL
+			w.line("URL;VALUE=URI", task.URL)
+		}
+		w.line("DESCRIPTION", icsText(description))
+		if p, ok := icsPriority[taskPriorityValue(task)]; ok {
+			w.line("PRIORITY", p)
+		}
+		if len(task.Tags) > 0 {
+			var tags []string
+			for _, t := range task.Tags {
+				tags = append(tags, icsText(t.Name))
+			}
+			w.line("CATEGORIES", strings.Join(tags, ","))
+		}
+		w.line("END", component)
+	}
+	w.line("END", "VCALENDAR")
+	return w.err
+}
+
+// --- ICALENDAR (CLI) ---
+
+var (
+	icsSpace     string
+	icsFolder    string
+	icsList      string
+	icsAssignees []string
+	icsTodos     bool
+	icsAddr      string
+	icsCache     time.Duration
+)
+
+// icsFeed is the calendar selected with the flags of export ics and serve
+// ics.
+type icsFeed struct {
+	apiToken string
+	teamID   string
+	name     string
+	where    string
+	members  *memberResolver
+	loc      *time.Location
+}
+
+// newICSFeed resolves the flags, exiting when a space, folder, list or
+// assignee can't be found.
+func newICSFeed() icsFeed {
+	apiToken, teamID := requireCredentials()
+	f := icsFeed{apiToken: apiToken, teamID: teamID, name: "ClickUp"}
+	f.members = &memberResolver{apiToken: apiToken, teamID: teamID}
+	var terms []string
+	if scope, ok := scopeFor(apiToken, teamID, icsSpace, icsFolder, icsList); ok {
+		terms = append(terms, scope.queryTerm())
+		f.name += " · " + scope.name
+	}
+	var ids []string
+	for _, a := range icsAssignees {
+		id, err := f.members.resolve(a)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		ids = append(ids, strconv.Itoa(id))
+	}
+	if len(ids) > 0 {
+		terms = append(terms, "assignee="+strings.Join(ids, ","))
+	}
+	f.where = strings.Join(terms, " ")
+	loc, err := f.members.location()
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	f.loc = loc
+	return f
+}
+
+func (f icsFeed) write(w io.Writer) error {
+	tasks, err := queryTasks(f.apiToken, f.teamID, f.where, f.members)
+	if err != nil {
+		return err
+	}
+	return writeICS(w, f.name, tasks, f.loc, icsTodos)
+}
+
+var exportICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Export tasks with a due date as an iCalendar file",
+	Long: `Write the tasks with a due date as iCalendar events, or as to-dos with
+--todos, to stdout. Without --space, --folder or --list, the tasks of the
+whole workspace are exported. Each entry has the task's status and URL, and
+a UID that stays the same between exports.`,
+	Example: `  clup export ics --space Engineering --assignee me > tasks.ics
+  clup export ics --list Sprint --todos > sprint.ics`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := newICSFeed().write(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error exporting tasks:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve tasks to other apps",
+}
+
+var serveICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Serve an iCalendar feed of tasks with a due date",
+	Long: `Serve the tasks that export ics writes as a feed calendar apps can
+subscribe to, at http://<addr>/tasks.ics. The tasks are fetched again when
+the feed is requested and the last fetch is older than --cache.`,
+	Example: `  clup serve ics --addr 127.0.0.1:8765 --assignee me`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		feed := newICSFeed()
+		var (
+			mu      sync.Mutex
+			cached  []byte
+			fetched time.Time
+		)
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" && r.URL.Path != "/tasks.ics" {
+				http.NotFound(w, r)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if cached == nil || time.Since(fetched) > icsCache {
+				var b strings.Builder
+				if err := feed.write(&b); err != nil {
+					fmt.Println("Error fetching tasks:", err)
+					if cached == nil {
+						http.Error(w, err.Error(), http.StatusBadGateway)
+						return
+					}
+				} else {
+					cached, fetched = []byte(b.String()), time.Now()
+				}
+			}
+			w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+			w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+			w.Write(cached)
+		})
+		server := &http.Server{Addr: icsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		fmt.Printf("Serving %s at http://%s/tasks.ics\n", feed.name, icsAddr)
+		if err := server.ListenAndServe(); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{exportICSCmd, serveICSCmd} {
+		c.Flags().StringVar(&icsSpace, "space", "", "space name or ID")
+		c.Flags().StringVar(&icsFolder, "folder", "", "folder name or ID")
+		c.Flags().StringVar(&icsList, "list", "", "list name or ID")
+		c.Flags().StringArrayVar(&icsAssignees, "assignee", nil, "only tasks of this assignee: me, a user ID, username or email (repeatable)")
+		c.Flags().BoolVar(&icsTodos, "todos", false, "write to-dos (VTODO) instead of events")
+	}
+	serveICSCmd.Flags().StringVar(&icsAddr, "addr", "127.0.0.1:8765", "address to listen on")
+	serveICSCmd.Flags().DurationVar(&icsCache, "cache", time.Minute, "how long fetched tasks are served before fetching them again")
+	exportCmd.AddCommand(exportICSCmd)
+	serveCmd.AddCommand(serveICSCmd)
+}
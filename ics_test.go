@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestICSText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain", "plain"},
+		{`a\b`, `a\\b`},
+		{"a;b,c", `a\;b\,c`},
+		{"line\nbreak\r\nthere", `line\nbreak\nthere`},
+	}
+	for _, tt := range tests {
+		if got := icsText(tt.in); got != tt.want {
+			t.Errorf("icsText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestICSFolding(t *testing.T) {
+	var b strings.Builder
+	w := &icsWriter{w: &b}
+	value := strings.Repeat("ab€", 40)
+	w.line("SUMMARY", value)
+	w.line("UID", "1@clickup.com")
+	if w.err != nil {
+		t.Fatal(w.err)
+	}
+	out := b.String()
+	if !strings.HasSuffix(out, "\r\nUID:1@clickup.com\r\n") {
+		t.Errorf("lines don't end with CRLF: %q", out)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("line %d has %d octets: %q", i, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a character: %q", i, line)
+		}
+		if i > 0 && lines[i] != "UID:1@clickup.com" && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %d doesn't start with a space: %q", i, line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if want := "SUMMARY:" + value + "\r\nUID:1@clickup.com\r\n"; unfolded != want {
+		t.Errorf("unfolded = %q, want %q", unfolded, want)
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	loc := time.UTC
+	ms := func(y int, m time.Month, d, h int) string {
+		return strconv.FormatInt(time.Date(y, m, d, h, 0, 0, 0, loc).UnixMilli(), 10)
+	}
+	meeting := Task{ID: "a1", Name: "Plan Q3; review, then ship", URL: "https://app.clickup.com/t/a1",
+		DueDate: ms(2026, time.March, 6, 15), StartDate: ms(2026, time.March, 6, 14), DateUpdated: ms(2026, time.March, 1, 8)}
+	meeting.Status.Status = "open"
+	meeting.List.Name = "Sprint"
+	meeting.Priority = &TaskPriority{ID: "2"}
+	meeting.Tags = []Tag{{Name: "q3"}, {Name: "a,b"}}
+	allDay := Task{ID: "b2", Name: "Release", DueDate: ms(2026, time.March, 9, 0)}
+	allDay.Status.Status, allDay.Status.Type = "done", "closed"
+	undated := Task{ID: "c3", Name: "Someday"}
+
+	var b strings.Builder
+	if err := writeICS(&b, "ClickUp · Eng", []Task{meeting, allDay, undated}, loc, false); err != nil {
+		t.Fatal(err)
+	}
+	out := strings.ReplaceAll(b.String(), "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:ClickUp · Eng\r\n",
+		"UID:a1@clickup.com\r\n",
+		"DTSTAMP:20260301T080000Z\r\n",
+		`SUMMARY:Plan Q3\; review\, then ship` + "\r\n",
+		"DTSTART:20260306T140000Z\r\nDTEND:20260306T150000Z\r\n",
+		`DESCRIPTION:Status: open\nList: Sprint\nhttps://app.clickup.com/t/a1` + "\r\n",
+		"PRIORITY:3\r\n",
+		`CATEGORIES:q3,a\,b` + "\r\n",
+		"UID:b2@clickup.com\r\n",
+		"DTSTART;VALUE=DATE:20260309\r\nDTEND;VALUE=DATE:20260310\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar has no %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "c3@clickup.com") {
+		t.Error("a task without a due date was exported")
+	}
+
+	b.Reset()
+	if err := writeICS(&b, "ClickUp", []Task{allDay}, loc, true); err != nil {
+		t.Fatal(err)
+	}
+	out = b.String()
+	for _, want := range []string{"BEGIN:VTODO\r\n", "DUE;VALUE=DATE:20260309\r\n", "STATUS:COMPLETED\r\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("to-do has no %q:\n%s", want, out)
+		}
+	}
+}
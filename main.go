@@ -6,9 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -39,11 +40,16 @@ var (
 
 // --- CLICKUP API ---
 type Task struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Content string `json:"description"`
-	Status  struct {
+	ID              string `json:"id"`
+	CustomID        string `json:"custom_id,omitempty"`
+	Name            string `json:"name"`
+	Content         string `json:"description"`
+	MarkdownContent string `json:"markdown_description,omitempty"`
+	Status          struct {
 		Status string `json:"status"`
+		Order  int    `json:"orderindex"`
+		Color  string `json:"color"`
+		Type   string `json:"type"`
 	} `json:"status"`
 	Space struct {
 		ID string `json:"id"`
@@ -55,10 +61,47 @@ type Task struct {
 	Folder struct {
 		Name string `json:"name"`
 	} `json:"folder"`
+	Assignees  []Member      `json:"assignees"`
+	Priority   *TaskPriority `json:"priority"`
+	Tags       []Tag         `json:"tags"`
+	URL        string        `json:"url"`
+	Parent     string        `json:"parent"`
+	Subtasks   []Task        `json:"subtasks"`
+	Checklists []Checklist   `json:"checklists"`
+	// Timestamps are Unix milliseconds encoded as strings.
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+	DueDate     string `json:"due_date"`
+	StartDate   string `json:"start_date"`
+}
+
+type Checklist struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Items []ChecklistItem `json:"items"`
+}
+
+type ChecklistItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Resolved bool   `json:"resolved"`
+}
+
+type TaskPriority struct {
+	ID       string `json:"id"`
+	Priority string `json:"priority"`
+	Color    string `json:"color"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+	Fg   string `json:"tag_fg"`
+	Bg   string `json:"tag_bg"`
 }
 
 type TasksResponse struct {
-	Tasks []Task `json:"tasks"`
+	Tasks    []Task `json:"tasks"`
+	LastPage bool   `json:"last_page"`
 }
 
 type Space struct {
@@ -75,8 +118,9 @@ type SpacesResponse struct {
 }
 
 type ListInfo struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	TaskCount taskCount `json:"task_count"`
 }
 
 func (l ListInfo) FilterValue() string { return l.Name }
@@ -118,10 +162,30 @@ type CommentsResponse struct {
 	Comments []Comment `json:"comments"`
 }
 
+// Time returns when a comment was posted. ClickUp sends Unix milliseconds.
+func (c Comment) Time() time.Time {
+	if t, ok := msTime(c.Date); ok {
+		return t
+	}
+	t, _ := time.Parse(time.RFC3339, c.Date)
+	return t
+}
+
+// Text returns the plain text of a comment.
+func (c Comment) Text() string {
+	var b strings.Builder
+	for _, part := range c.Comment {
+		b.WriteString(part.Text)
+	}
+	return b.String()
+}
+
 type Member struct {
 	ID       int    `json:"id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	Initials string `json:"initials,omitempty"`
+	Color    string `json:"color,omitempty"`
 }
 
 func (m Member) FilterValue() string { return m.Username }
@@ -132,6 +196,29 @@ type MembersResponse struct {
 	Members []Member `json:"members"`
 }
 
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Timezone string `json:"timezone"`
+}
+
+type UserResponse struct {
+	User User `json:"user"`
+}
+
+type Team struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Members []struct {
+		User Member `json:"user"`
+	} `json:"members"`
+}
+
+type TeamsResponse struct {
+	Teams []Team `json:"teams"`
+}
+
 func (s Status) FilterValue() string { return s.Status }
 func (s Status) Title() string       { return s.Status }
 func (s Status) Description() string { return "" }
@@ -247,6 +334,27 @@ const (
 	taskCreatedView
 	deleteConfirmationView
 	taskDeletedView
+	bulkStatusView
+	bulkAssigneeView
+	bulkPriorityView
+	bulkTagView
+	bulkDeleteConfirmationView
+	bulkProgressView
+	duplicateTaskView
+	createTaskTemplateView
+	createTaskVarsView
+	createTaskReviewView
+	profileSelectionView
+	taskLoadingView
+	gotoTaskView
+	containerNameView
+	containerConfirmationView
+	listTemplateView
+	viewSelectionView
+	viewNameView
+	tagPickerView
+	taskDatesView
+	calendarView
 )
 
 const (
@@ -255,6 +363,8 @@ const (
 	statusFocus
 	assigneeFocus
 	priorityFocus
+	dueFocus
+	startFocus
 )
 
 type model struct {
@@ -290,6 +400,7 @@ type model struct {
 	newTaskTitle      string
 	newTaskDesc       string
 	newTaskStatus     string
+	newTaskStatuses   []list.Item
 	newTaskAssignees  []int
 	newTaskPriority   int
 	selectedTask      Task
@@ -298,42 +409,92 @@ type model struct {
 	comments          []Comment
 	commentsLoaded    bool
 	allLists          []list.Item
-}
-
-func newModel(apiToken, teamID string, creatingTask bool) model {
-	if apiToken == "" || teamID == "" {
-		m := model{
-			state:  formView,
-			inputs: make([]textinput.Model, 2),
-		}
-		var t textinput.Model
-		for i := range m.inputs {
-			t = textinput.New()
-			t.Cursor.Style = cursorStyle
-			t.CharLimit = 128
-			switch i {
-			case 0:
-				t.Placeholder = "ClickUp API Token"
-				t.Focus()
-				t.PromptStyle = focusedStyle
-			case 1:
-				t.Placeholder = "Team ID"
-			}
-			m.inputs[i] = t
-		}
-		return m
-	}
-
-	sl := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-	sl.Title = "Select a Space"
-	return model{
+	markedTasks       map[string]struct{}
+	visualMode        bool
+	visualAnchor      int
+	movingTasks       bool
+	tagInput          textinput.Model
+	bulkUnassign      bool
+	bulkTasks         []Task
+	bulkOp            bulkOp
+	bulkResults       []bulkResult
+	duplicateOptions  duplicateOptions
+	templateList      list.Model
+	template          *taskTemplate
+	templateVars      []templateVar
+	templateVarIndex  int
+	templateValues    map[string]string
+	varInput          textinput.Model
+	newTaskTags       []string
+	newTaskChecklist  []string
+	currentUser       *User
+	reviewing         bool
+	createdTask       Task
+	profile           string
+	profileList       list.Model
+	previousState     viewState
+	defaultSpace      string
+	defaultList       string
+	formError         error
+	formTeams         []Team
+	formTeamIndex     int
+	formSeq           int
+	openTaskRef       string
+	openAction        taskAction
+	gotoInput         textinput.Model
+	gotoErr           error
+	tree              hierarchy
+	scope             taskScope
+	tasks             []Task
+	order             taskOrder
+	stats             taskStats
+	containerOp       containerOp
+	nameInput         textinput.Model
+	listTemplates     list.Model
+	viewList          list.Model
+	tagList           list.Model
+	tagChoice         map[string]Tag
+	editTags          map[string]Tag
+	tagFilter         map[string]Tag
+	dateInputs        []textinput.Model
+	dateFocus         int
+	dateErr           error
+	newTaskDue        taskDate
+	newTaskStart      taskDate
+	editDue           taskDate
+	editStart         taskDate
+	agenda            bool
+	calendarDay       time.Time
+}
+
+func newModel(apiToken, teamID, profileName string, creatingTask bool) model {
+	m := model{
 		state:             spaceSelectionView,
-		spaceList:         sl,
+		spaceList:         newSpaceTree(spaceListTitle(profileName), 0, 0),
+		tree:              newHierarchy(),
 		apiToken:          apiToken,
 		teamID:            teamID,
+		profile:           profileName,
 		isCreatingTask:    creatingTask,
+		defaultSpace:      settings.DefaultSpace,
+		defaultList:       settings.DefaultList,
 		selectedAssignees: make(map[int]struct{}),
+		markedTasks:       make(map[string]struct{}),
 	}
+	switch {
+	case apiToken == "" || teamID == "":
+		m.state = formView
+		m.inputs = []textinput.Model{newTokenInput()}
+		m.spinner = spinner.New()
+	case creatingTask:
+	case settings.DefaultView != "":
+		m, _ = m.startTaskList(viewScope(settings.DefaultView))
+	case len(settings.Views) > 0:
+		// The view picker replaces the space tree as the start screen, so
+		// the default space only applies once the tree is opened from it.
+		m = m.startViewSelection()
+	}
+	return m
 }
 
 // --- COMMANDS ---
@@ -346,8 +507,7 @@ func fetchSpacesCmd(apiToken, teamID string) tea.Cmd {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -375,8 +535,7 @@ func fetchFolderlessListsCmd(apiToken, spaceID string) tea.Cmd {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -404,8 +563,7 @@ func fetchFoldersWithListsCmd(apiToken, spaceID string) tea.Cmd {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -433,8 +591,7 @@ func fetchAssigneesCmd(apiToken, listID string) tea.Cmd {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -454,52 +611,53 @@ func fetchAssigneesCmd(apiToken, listID string) tea.Cmd {
 	}
 }
 
-func createTaskCmd(apiToken, listID, name, description, status string, assignees []int, priority int) tea.Cmd {
+func fetchAllTasksCmd(apiToken, teamID string) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("https://api.clickup.com/api/v2/list/%s/task", listID)
-		payloadMap := map[string]interface{}{
-			"name":        name,
-			"description": description,
-			"status":      status,
-			"assignees":   assignees,
-		}
-		if priority > 0 {
-			payloadMap["priority"] = priority
-		}
-		payload, err := json.Marshal(payloadMap)
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/team/%s/task", teamID)
+		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
 			return err
 		}
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+		req.Header.Set("Authorization", apiToken)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
-		req.Header.Set("Authorization", apiToken)
-		req.Header.Set("Content-Type", "application/json")
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return err
 		}
-		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("create task failed: %s", string(body))
+			return fmt.Errorf("fetch all tasks failed: %s", string(body))
 		}
-		return "create_success"
+		var tasksResponse TasksResponse
+		if err := json.Unmarshal(body, &tasksResponse); err != nil {
+			return err
+		}
+		return tasksResponse
 	}
 }
 
-func fetchAllTasksCmd(apiToken, teamID string) tea.Cmd {
+// fetchTasksCmd loads the tasks of a space, folder, list or saved view for
+// the task list, applying the configured default filter.
+func fetchTasksCmd(apiToken, teamID string, scope taskScope) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("https://api.clickup.com/api/v2/team/%s/task", teamID)
+		if settings.Filter != "" || scope.kind == scopeView {
+			members := &memberResolver{apiToken: apiToken, teamID: teamID}
+			tasks, err := queryTasks(apiToken, teamID, scope.where(), members)
+			if err != nil {
+				return err
+			}
+			return taskListMsg(tasks)
+		}
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/team/%s/task?%s", teamID, scope.query().Encode())
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -509,26 +667,64 @@ func fetchAllTasksCmd(apiToken, teamID string) tea.Cmd {
 			return err
 		}
 		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("fetch all tasks failed: %s", string(body))
+			return fmt.Errorf("fetch tasks failed: %s", string(body))
 		}
 		var tasksResponse TasksResponse
 		if err := json.Unmarshal(body, &tasksResponse); err != nil {
 			return err
 		}
-		return tasksResponse
+		return taskListMsg(tasksResponse.Tasks)
 	}
 }
 
-func fetchTasksCmd(apiToken, teamID, spaceID string) tea.Cmd {
+// taskListMsg carries the tasks of the task list.
+type taskListMsg []Task
+
+// fetchFilteredTasksCmd queries the workspace for tasks matching query and
+// follows pagination until the last page.
+func fetchFilteredTasksCmd(apiToken, teamID string, query neturl.Values) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("https://api.clickup.com/api/v2/team/%s/task?space_ids[]=%s", teamID, spaceID)
-		req, err := http.NewRequest("GET", url, nil)
+		var all TasksResponse
+		for page := 0; ; page++ {
+			query.Set("page", fmt.Sprint(page))
+			url := fmt.Sprintf("https://api.clickup.com/api/v2/team/%s/task?%s", teamID, query.Encode())
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Authorization", apiToken)
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return err
+			}
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("fetch tasks failed: %s", string(body))
+			}
+			var tasksResponse TasksResponse
+			if err := json.Unmarshal(body, &tasksResponse); err != nil {
+				return err
+			}
+			all.Tasks = append(all.Tasks, tasksResponse.Tasks...)
+			if tasksResponse.LastPage || len(tasksResponse.Tasks) == 0 {
+				return all
+			}
+		}
+	}
+}
+
+func fetchUserCmd(apiToken string) tea.Cmd {
+	return func() tea.Msg {
+		req, err := http.NewRequest("GET", "https://api.clickup.com/api/v2/user", nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -538,30 +734,25 @@ func fetchTasksCmd(apiToken, teamID, spaceID string) tea.Cmd {
 			return err
 		}
 		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("fetch tasks failed: %s", string(body))
+			return fmt.Errorf("fetch user failed: %s", string(body))
 		}
-		var tasksResponse TasksResponse
-		if err := json.Unmarshal(body, &tasksResponse); err != nil {
+		var userResponse UserResponse
+		if err := json.Unmarshal(body, &userResponse); err != nil {
 			return err
 		}
-		items := make([]list.Item, len(tasksResponse.Tasks))
-		for i, task := range tasksResponse.Tasks {
-			items[i] = task
-		}
-		return items
+		return userResponse
 	}
 }
 
 func fetchTaskDetailsCmd(apiToken, taskID string) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("https://api.clickup.com/api/v2/task/%s", taskID)
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/task/%s?include_subtasks=true", taskID)
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -582,15 +773,23 @@ func fetchTaskDetailsCmd(apiToken, taskID string) tea.Cmd {
 }
 
 func fetchCommentsCmd(apiToken, taskID string) tea.Cmd {
+	return fetchCommentPageCmd(apiToken, taskID, nil)
+}
+
+// fetchCommentPageCmd loads a page of comments, newest first, starting after
+// the comment given with the start (its date) and start_id parameters.
+func fetchCommentPageCmd(apiToken, taskID string, query neturl.Values) tea.Cmd {
 	return func() tea.Msg {
 		url := fmt.Sprintf("https://api.clickup.com/api/v2/task/%s/comment", taskID)
+		if len(query) > 0 {
+			url += "?" + query.Encode()
+		}
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -618,8 +817,7 @@ func fetchStatusesCmd(apiToken, spaceID string) tea.Cmd {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -657,8 +855,7 @@ func postCommentCmd(apiToken, taskID, commentText string) tea.Cmd {
 		}
 		req.Header.Set("Authorization", apiToken)
 		req.Header.Set("Content-Type", "application/json")
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -694,8 +891,7 @@ func updateTaskCmd(apiToken, taskID, description, status string) tea.Cmd {
 		}
 		req.Header.Set("Authorization", apiToken)
 		req.Header.Set("Content-Type", "application/json")
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -716,8 +912,7 @@ func deleteTaskCmd(apiToken, taskID string) tea.Cmd {
 			return err
 		}
 		req.Header.Set("Authorization", apiToken)
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -730,19 +925,131 @@ func deleteTaskCmd(apiToken, taskID string) tea.Cmd {
 	}
 }
 
-func saveCredentialsCmd(apiToken, teamID string) tea.Cmd {
+func updateTaskFieldsCmd(apiToken, taskID string, fields map[string]interface{}) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/task/%s", taskID)
+		payload, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("update task failed: %s", string(body))
+		}
+		return "refresh_list_success"
+	}
+}
+
+// moveTaskCmd changes the home list of a task. The v2 API can only add tasks
+// to additional lists, so this goes through the v3 endpoint.
+func moveTaskCmd(apiToken, teamID, taskID, listID string) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v3/workspaces/%s/tasks/%s/home_list/%s", teamID, taskID, listID)
+		req, err := http.NewRequest("PUT", url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("move task failed: %s", string(body))
+		}
+		return "refresh_list_success"
+	}
+}
+
+func addTagCmd(apiToken, taskID, tagName string) tea.Cmd {
 	return func() tea.Msg {
-		home, err := os.UserHomeDir()
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/task/%s/tag/%s", taskID, neturl.PathEscape(tagName))
+		req, err := http.NewRequest("POST", url, nil)
 		if err != nil {
-			home = "." // Fallback to current directory
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("add tag failed: %s", string(body))
+		}
+		return "refresh_list_success"
+	}
+}
+
+func removeTagCmd(apiToken, taskID, tagName string) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/task/%s/tag/%s", taskID, neturl.PathEscape(tagName))
+		req, err := http.NewRequest("DELETE", url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("remove tag failed: %s", string(body))
+		}
+		return "refresh_list_success"
+	}
+}
+
+func fetchTeamsCmd(apiToken string) tea.Cmd {
+	return func() tea.Msg {
+		req, err := http.NewRequest("GET", "https://api.clickup.com/api/v2/team", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("fetch teams failed: %s", string(body))
+		}
+		var teamsResponse TeamsResponse
+		if err := json.Unmarshal(body, &teamsResponse); err != nil {
+			return err
 		}
-		filePath := filepath.Join(home, ".clup.env")
-		content := fmt.Sprintf("CLICKUP_API_TOKEN=\"%s\"\nCLICKUP_TEAM_ID=\"%s\"\n", apiToken, teamID)
-		err = os.WriteFile(filePath, []byte(content), 0600)
+		return teamsResponse
+	}
+}
+
+func saveCredentialsCmd(apiToken, teamID, profileName string) tea.Cmd {
+	return func() tea.Msg {
+		store, err := newCredentialStore(settings.Credentials, false)
 		if err != nil {
 			return err
 		}
-		return nil
+		return saveLogin(settings, store, profileName, apiToken, teamID)
 	}
 }
 
@@ -755,12 +1062,14 @@ func (m model) Init() tea.Cmd {
 	case spaceSelectionView:
 		return fetchSpacesCmd(m.apiToken, m.teamID)
 	case listView:
-		return fetchTasksCmd(m.apiToken, m.teamID, m.spaceID)
+		return fetchTasksCmd(m.apiToken, m.teamID, m.scope)
 	case listSelectionView:
 		return tea.Batch(
 			fetchFolderlessListsCmd(m.apiToken, m.spaceID),
 			fetchFoldersWithListsCmd(m.apiToken, m.spaceID),
 		)
+	case taskLoadingView:
+		return tea.Batch(m.spinner.Tick, fetchTaskRefCmd(m.apiToken, m.teamID, m.openTaskRef))
 	default:
 		return textinput.Blink
 	}
@@ -776,6 +1085,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 		}
+	case taskListMsg:
+		// Tasks of the current space, which may arrive while a task is
+		// shown on top of the list.
+		m.loading = false
+		m.tasks = msg
+		m.showTasks()
+		if m.currentUser == nil {
+			return m, tea.Batch(m.fetchSelectedCounts(), fetchListUserCmd(m.apiToken))
+		}
+		return m, m.fetchSelectedCounts()
+	case listUserMsg:
+		if m.currentUser == nil {
+			user := User(msg)
+			m.currentUser = &user
+			m.refreshTaskDelegate()
+			m.showTasks()
+		}
+		return m, nil
+	case taskCountsMsg:
+		m.stats.counts[msg.taskID] = msg.counts
+		return m, nil
 	}
 
 	switch m.state {
@@ -809,6 +1139,48 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return updateDeleteConfirmation(msg, m)
 	case taskDeletedView:
 		return updateTaskDeleted(msg, m)
+	case bulkStatusView:
+		return updateBulkStatus(msg, m)
+	case bulkAssigneeView:
+		return updateBulkAssignee(msg, m)
+	case bulkPriorityView:
+		return updateBulkPriority(msg, m)
+	case bulkTagView:
+		return updateBulkTag(msg, m)
+	case bulkDeleteConfirmationView:
+		return updateBulkDeleteConfirmation(msg, m)
+	case bulkProgressView:
+		return updateBulkProgress(msg, m)
+	case duplicateTaskView:
+		return updateDuplicateTask(msg, m)
+	case createTaskTemplateView:
+		return updateCreateTaskTemplate(msg, m)
+	case createTaskVarsView:
+		return updateCreateTaskVars(msg, m)
+	case createTaskReviewView:
+		return updateCreateTaskReview(msg, m)
+	case profileSelectionView:
+		return updateProfileSelection(msg, m)
+	case taskLoadingView:
+		return updateTaskLoading(msg, m)
+	case gotoTaskView:
+		return updateGotoTask(msg, m)
+	case containerNameView:
+		return updateContainerName(msg, m)
+	case containerConfirmationView:
+		return updateContainerConfirmation(msg, m)
+	case listTemplateView:
+		return updateListTemplate(msg, m)
+	case viewSelectionView:
+		return updateViewSelection(msg, m)
+	case viewNameView:
+		return updateViewName(msg, m)
+	case tagPickerView:
+		return updateTagPicker(msg, m)
+	case taskDatesView:
+		return updateTaskDates(msg, m)
+	case calendarView:
+		return updateCalendar(msg, m)
 	}
 	return m, nil
 }
@@ -855,65 +1227,276 @@ func (m model) View() string {
 		return m.viewEditTask()
 	case deleteConfirmationView:
 		return m.viewDeleteConfirmation()
+	case bulkStatusView:
+		return appStyle.Render(m.statusList.View())
+	case bulkAssigneeView:
+		return appStyle.Render(m.assigneeList.View())
+	case bulkPriorityView:
+		return appStyle.Render(m.priorityList.View())
+	case bulkTagView:
+		return m.viewBulkTag()
+	case bulkDeleteConfirmationView:
+		return m.viewBulkDeleteConfirmation()
+	case bulkProgressView:
+		return m.viewBulkProgress()
+	case duplicateTaskView:
+		return m.viewDuplicateTask()
+	case createTaskTemplateView:
+		return appStyle.Render(m.templateList.View())
+	case createTaskVarsView:
+		return m.viewCreateTaskVars()
+	case createTaskReviewView:
+		return m.viewCreateTaskReview()
+	case profileSelectionView:
+		return appStyle.Render(m.profileList.View())
+	case taskLoadingView:
+		return m.viewTaskLoading()
+	case gotoTaskView:
+		return m.viewGotoTask()
+	case containerNameView:
+		return m.viewContainerName()
+	case containerConfirmationView:
+		return m.viewContainerConfirmation()
+	case listTemplateView:
+		return appStyle.Render(m.listTemplates.View())
+	case viewSelectionView:
+		return appStyle.Render(m.viewList.View())
+	case viewNameView:
+		return m.viewViewName()
+	case tagPickerView:
+		return appStyle.Render(m.tagList.View())
+	case taskDatesView:
+		return m.viewTaskDates()
+	case calendarView:
+		return m.viewCalendar()
 	}
 	return ""
 }
 
 // --- UPDATE & VIEW (FORM) ---
+
+// The first-run form checks the API token against /user, while typing and on
+// enter, and then offers the workspaces from /team instead of asking for the
+// numeric team ID.
+
+const (
+	tokenFocus int = iota
+	workspaceFocus
+)
+
+// tokenCheckDelay is how long typing has to pause before the token is checked.
+const tokenCheckDelay = 600 * time.Millisecond
+
+type tokenEditedMsg struct {
+	seq int
+}
+
+type tokenCheckedMsg struct {
+	token string
+	user  User
+	teams []Team
+	err   error
+}
+
+// checkTokenCmd validates a token and loads the workspaces it can access.
+func checkTokenCmd(apiToken string) tea.Cmd {
+	return func() tea.Msg {
+		checked := tokenCheckedMsg{token: apiToken}
+		req, err := http.NewRequest("GET", "https://api.clickup.com/api/v2/user", nil)
+		if err != nil {
+			checked.err = err
+			return checked
+		}
+		req.Header.Set("Authorization", apiToken)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			checked.err = fmt.Errorf("cannot reach ClickUp: %v", err)
+			return checked
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			checked.err = err
+			return checked
+		}
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			checked.err = fmt.Errorf("invalid API token")
+			return checked
+		case resp.StatusCode != http.StatusOK:
+			checked.err = fmt.Errorf("checking the token failed: %s", string(body))
+			return checked
+		}
+		var userResponse UserResponse
+		if err := json.Unmarshal(body, &userResponse); err != nil {
+			checked.err = err
+			return checked
+		}
+		checked.user = userResponse.User
+
+		switch msg := fetchTeamsCmd(apiToken)().(type) {
+		case error:
+			checked.err = msg
+		case TeamsResponse:
+			checked.teams = msg.Teams
+			if len(msg.Teams) == 0 {
+				checked.err = fmt.Errorf("this token has no access to any workspace")
+			}
+		}
+		return checked
+	}
+}
+
+func newTokenInput() textinput.Model {
+	t := textinput.New()
+	t.Cursor.Style = cursorStyle
+	t.CharLimit = 128
+	t.Placeholder = "ClickUp API Token"
+	t.EchoMode = textinput.EchoPassword
+	t.Focus()
+	t.PromptStyle = focusedStyle
+	return t
+}
+
+func (m model) focusForm(index int) (model, tea.Cmd) {
+	m.focusIndex = index
+	if index == tokenFocus {
+		m.inputs[0].PromptStyle = focusedStyle
+		return m, m.inputs[0].Focus()
+	}
+	m.inputs[0].Blur()
+	m.inputs[0].PromptStyle = noStyle
+	return m, nil
+}
+
 func updateForm(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd = make([]tea.Cmd, len(m.inputs))
+	var cmd tea.Cmd
 	switch msg := msg.(type) {
+	case tokenEditedMsg:
+		token := strings.TrimSpace(m.inputs[0].Value())
+		if msg.seq != m.formSeq || len(token) < 10 {
+			return m, nil
+		}
+		m.loading = true
+		return m, tea.Batch(m.spinner.Tick, checkTokenCmd(token))
+	case tokenCheckedMsg:
+		if msg.token != strings.TrimSpace(m.inputs[0].Value()) {
+			return m, nil // The token was edited since.
+		}
+		m.loading = false
+		m.formError = msg.err
+		if msg.err != nil {
+			m.formTeams = nil
+			return m, nil
+		}
+		m.currentUser = &msg.user
+		m.formTeams = msg.teams
+		m.formTeamIndex = 0
+		for i, team := range msg.teams {
+			if team.ID == m.teamID {
+				m.formTeamIndex = i
+			}
+		}
+		return m.focusForm(workspaceFocus)
+	case spinner.TickMsg:
+		if m.loading {
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "tab", "shift+tab", "enter", "up", "down":
-			s := msg.String()
-			if s == "enter" && m.focusIndex == len(m.inputs)-1 {
-				m.apiToken = m.inputs[0].Value()
-				m.teamID = m.inputs[1].Value()
+		case "esc":
+			m.quitting = true
+			return m, tea.Quit
+		case "tab", "shift+tab":
+			if len(m.formTeams) == 0 {
+				return m, nil
+			}
+			return m.focusForm(1 - m.focusIndex)
+		case "up", "k", "down", "j":
+			if m.focusIndex != workspaceFocus {
+				break
+			}
+			if s := msg.String(); s == "up" || s == "k" {
+				if m.formTeamIndex > 0 {
+					m.formTeamIndex--
+				}
+			} else if m.formTeamIndex < len(m.formTeams)-1 {
+				m.formTeamIndex++
+			}
+			return m, nil
+		case "enter":
+			if m.focusIndex == workspaceFocus && len(m.formTeams) > 0 {
+				m.apiToken = strings.TrimSpace(m.inputs[0].Value())
+				m.teamID = m.formTeams[m.formTeamIndex].ID
 				m.state = spaceSelectionView
-				sl := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-				sl.Title = "Select a Space"
-				m.spaceList = sl
+				h, v := appStyle.GetFrameSize()
+				m.spaceList.SetSize(m.width-h, m.height-v)
 				return m, tea.Batch(
-					saveCredentialsCmd(m.apiToken, m.teamID),
+					saveCredentialsCmd(m.apiToken, m.teamID, m.profile),
 					fetchSpacesCmd(m.apiToken, m.teamID),
 				)
 			}
-			if s == "up" || s == "shift+tab" {
-				m.focusIndex--
-			} else {
-				m.focusIndex++
-			}
-			if m.focusIndex > len(m.inputs) {
-				m.focusIndex = 0
-			} else if m.focusIndex < 0 {
-				m.focusIndex = len(m.inputs) - 1
-			}
-			for i := range m.inputs {
-				if i == m.focusIndex {
-					cmds[i] = m.inputs[i].Focus()
-					m.inputs[i].PromptStyle = focusedStyle
-				} else {
-					m.inputs[i].Blur()
-					m.inputs[i].PromptStyle = noStyle
-				}
+			token := strings.TrimSpace(m.inputs[0].Value())
+			if token == "" {
+				m.formError = fmt.Errorf("enter your API token")
+				return m, nil
 			}
-			return m, tea.Batch(cmds...)
+			m.formSeq++
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, checkTokenCmd(token))
 		}
 	}
-	for i := range m.inputs {
-		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	if m.focusIndex != tokenFocus {
+		return m, nil
 	}
-	return m, tea.Batch(cmds...)
+	before := m.inputs[0].Value()
+	m.inputs[0], cmd = m.inputs[0].Update(msg)
+	if m.inputs[0].Value() == before {
+		return m, cmd
+	}
+	// Check the token once typing pauses.
+	m.formSeq++
+	m.formError = nil
+	m.formTeams = nil
+	seq := m.formSeq
+	return m, tea.Batch(cmd, tea.Tick(tokenCheckDelay, func(time.Time) tea.Msg {
+		return tokenEditedMsg{seq: seq}
+	}))
 }
 
 func (m model) viewForm() string {
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("Enter ClickUp Credentials") + "\n\n")
-	for i := range m.inputs {
-		b.WriteString(m.inputs[i].View() + "\n")
+	b.WriteString(m.inputs[0].View() + "\n")
+	switch {
+	case m.loading:
+		b.WriteString(m.spinner.View() + " Checking token...\n")
+	case m.formError != nil:
+		b.WriteString(failureStyle.Render("✗ "+m.formError.Error()) + "\n")
+	case m.currentUser != nil && len(m.formTeams) > 0:
+		b.WriteString(statusMessageStyle("✓ Logged in as "+m.currentUser.Username) + "\n")
+	default:
+		b.WriteString(helpStyle.Render("Find your token in ClickUp under Settings > Apps.") + "\n")
+	}
+	if len(m.formTeams) > 0 {
+		b.WriteString("\nWorkspace:\n")
+		for i, team := range m.formTeams {
+			line := fmt.Sprintf("%s (%s)", team.Name, team.ID)
+			switch {
+			case i == m.formTeamIndex && m.focusIndex == workspaceFocus:
+				b.WriteString(focusedStyle.Render("> "+line) + "\n")
+			case i == m.formTeamIndex:
+				b.WriteString("> " + line + "\n")
+			default:
+				b.WriteString("  " + line + "\n")
+			}
+		}
+		b.WriteString(helpStyle.Render("\nenter to continue • ↑/↓ to pick a workspace • tab to edit the token • esc to quit"))
+	} else {
+		b.WriteString(helpStyle.Render("\nenter to check the token • esc to quit"))
 	}
-	b.WriteString(helpStyle.Render("\nenter to submit • tab to navigate • esc to quit"))
 	return appStyle.Render(b.String())
 }
 
@@ -925,52 +1508,141 @@ func updateSpaceSelection(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		h, v := appStyle.GetFrameSize()
 		m.spaceList.SetSize(msg.Width-h, msg.Height-v)
 	case SpacesResponse:
-		items := make([]list.Item, len(msg.Spaces))
-		for i, s := range msg.Spaces {
-			items[i] = s
+		m.tree.spaces = msg.Spaces
+		m.setTreeItems()
+		if m.defaultSpace != "" {
+			name := m.defaultSpace
+			m.defaultSpace = ""
+			for _, s := range msg.Spaces {
+				if s.ID == name || strings.EqualFold(s.Name, name) {
+					return m.selectSpace(s)
+				}
+			}
 		}
-		m.spaceList.SetItems(items)
 	case error:
 		m.err = msg
 		return m, tea.Quit
+	case spaceTreeMsg:
+		delete(m.tree.loading, msg.spaceID)
+		m.tree.folders[msg.spaceID] = msg.folders
+		m.tree.lists[msg.spaceID] = msg.lists
+		m.setTreeItems()
+		return m, nil
+	case containerDoneMsg:
+		return m, tea.Batch(
+			m.spaceList.NewStatusMessage(statusMessageStyle(msg.status)),
+			fetchSpaceTreeCmd(m.apiToken, msg.spaceID),
+		)
 	case tea.KeyMsg:
-		if msg.String() == "enter" {
-			selected, ok := m.spaceList.SelectedItem().(Space)
-			if ok {
-				m.spaceID = selected.ID
-				if m.isCreatingTask {
-					m.state = listSelectionView
-					ll := list.New([]list.Item{}, list.NewDefaultDelegate(), m.width, m.height)
-					ll.Title = "Select a List in " + selected.Name
-					m.folderlessList = ll
-					return m, tea.Batch(
-						fetchFolderlessListsCmd(m.apiToken, m.spaceID),
-						fetchFoldersWithListsCmd(m.apiToken, m.spaceID),
-					)
-				}
-				m.state = listView
-				h, v := appStyle.GetFrameSize()
-				l := list.New([]list.Item{}, list.NewDefaultDelegate(), m.width-h, m.height-v)
-				l.Title = "Tasks in " + selected.Name
-				l.SetShowStatusBar(true)
-				l.SetFilteringEnabled(true)
-				l.AdditionalShortHelpKeys = func() []key.Binding {
-					return []key.Binding{
-						key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "view")),
-						key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
-						key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
-					}
-				}
-				m.list = l
-				return m, fetchTasksCmd(m.apiToken, m.teamID, m.spaceID)
+		if m.spaceList.FilterState() == list.Filtering {
+			break
+		}
+		if key.Matches(msg, keys.SwitchWorkspace) {
+			return m.startProfileSelection()
+		}
+		if msg.String() == "esc" && m.spaceList.FilterState() == list.Unfiltered && !m.isCreatingTask && len(settings.Views) > 0 {
+			return m.startViewSelection(), nil
+		}
+		selected, ok := m.spaceList.SelectedItem().(treeNode)
+		if !ok {
+			break
+		}
+		if !m.isCreatingTask {
+			if updated, cmd, handled := updateTreeActions(msg, m, selected); handled {
+				return updated, cmd
 			}
 		}
+		switch {
+		case msg.String() == "enter":
+			return m.openTreeNode(selected)
+		case key.Matches(msg, keys.Expand):
+			return m.expandTreeNode(selected)
+		case key.Matches(msg, keys.Collapse):
+			return m.collapseTreeNode(selected), nil
+		}
 	}
 	m.spaceList, cmd = m.spaceList.Update(msg)
 	return m, cmd
 }
 
+func (m model) selectSpace(selected Space) (model, tea.Cmd) {
+	m.spaceID = selected.ID
+	m.scope = taskScope{kind: scopeSpace, id: selected.ID, name: selected.Name}
+	if m.isCreatingTask {
+		return m.startListSelection(m.spaceID, "Select a List in "+selected.Name)
+	}
+	return m.startTaskList(m.scope)
+}
+
+// startTaskList opens the task list of a space, folder or list.
+func (m model) startTaskList(scope taskScope) (model, tea.Cmd) {
+	m.state = listView
+	m.scope = scope
+	m.order = settings.orderFor(m.spaceID)
+	if scope.kind == scopeView {
+		m.order = settings.Views[scope.id].order()
+	}
+	m.tagFilter = nil
+	m.agenda = false
+	m.calendarDay = time.Time{}
+	h, v := appStyle.GetFrameSize()
+	m.stats = newTaskStats()
+	l := newList([]list.Item{}, newTaskDelegate(m.markedTasks, false, 0, m.stats, userLocation(m.currentUser)), m.width-h, m.height-v)
+	l.Title = m.taskListTitle()
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(true)
+	// g opens the go to task prompt instead, and esc goes back to the tree.
+	l.KeyMap.GoToStart.SetKeys("home")
+	l.KeyMap.GoToStart.SetHelp("home", "go to start")
+	l.KeyMap.Quit.SetKeys("q")
+	l.KeyMap.Quit.SetHelp("q", "quit")
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.View, keys.Edit, keys.Delete}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return append(bulkHelpKeys(), keys.Duplicate, keys.GoTo, keys.Sort, keys.ReverseSort, keys.Group, keys.TagFilter, keys.Agenda, keys.Calendar, keys.SaveView, keys.SwitchWorkspace)
+	}
+	m.list = l
+	return m, fetchTasksCmd(m.apiToken, m.teamID, m.scope)
+}
+
+// taskListTitle names the scope of the task list and the tags it is filtered
+// by.
+func (m model) taskListTitle() string {
+	title := "Tasks in " + m.scope.name
+	switch {
+	case m.agenda:
+		title = "Agenda of " + m.scope.name
+	case !m.calendarDay.IsZero():
+		title += " due " + m.calendarDay.Format("Mon, Jan 2")
+	}
+	if len(m.tagFilter) > 0 {
+		var names []string
+		for _, t := range sortedTags(m.tagFilter) {
+			names = append(names, "#"+t.Name)
+		}
+		title += " " + strings.Join(names, " ")
+	}
+	return title
+}
+
 // --- UPDATE & VIEW (LIST SELECTION) ---
+
+// startListSelection lets the user pick one of the folderless or folder lists
+// of a space, either for a new task or to move tasks into.
+func (m model) startListSelection(spaceID, title string) (model, tea.Cmd) {
+	m.state = listSelectionView
+	m.allLists = nil
+	h, v := appStyle.GetFrameSize()
+	ll := newList([]list.Item{}, newItemDelegate(), m.width-h, m.height-v)
+	ll.Title = title
+	m.folderlessList = ll
+	return m, tea.Batch(
+		fetchFolderlessListsCmd(m.apiToken, spaceID),
+		fetchFoldersWithListsCmd(m.apiToken, spaceID),
+	)
+}
+
 func updateListSelection(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -982,6 +1654,9 @@ func updateListSelection(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			m.allLists = append(m.allLists, l)
 		}
 		m.folderlessList.SetItems(m.allLists)
+		if selected, ok := m.matchDefaultList(); ok {
+			return m.selectList(selected)
+		}
 	case FoldersResponse:
 		for _, f := range msg.Folders {
 			for _, l := range f.Lists {
@@ -990,19 +1665,35 @@ func updateListSelection(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			}
 		}
 		m.folderlessList.SetItems(m.allLists)
+		if selected, ok := m.matchDefaultList(); ok {
+			return m.selectList(selected)
+		}
 	case error:
 		m.err = msg
 		return m, tea.Quit
 	case tea.KeyMsg:
+		if m.movingTasks && m.folderlessList.FilterState() != list.Filtering {
+			switch msg.String() {
+			case "esc":
+				m.movingTasks = false
+				m.state = listView
+				return m, nil
+			case "enter":
+				selected, ok := m.folderlessList.SelectedItem().(ListInfo)
+				if ok {
+					m.movingTasks = false
+					return m.startBulk(bulkOp{kind: bulkMove, listID: selected.ID, listName: selected.Name})
+				}
+			}
+		}
+		if msg.String() == "esc" && m.folderlessList.FilterState() == list.Unfiltered {
+			m.state = spaceSelectionView
+			return m, nil
+		}
 		if msg.String() == "enter" {
 			selected, ok := m.folderlessList.SelectedItem().(ListInfo)
 			if ok {
-				m.listID = selected.ID
-				m.state = createTaskTitleView
-				m.titleInput = textinput.New()
-				m.titleInput.Placeholder = "Task Title"
-				m.titleInput.Focus()
-				return m, nil
+				return m.selectList(selected)
 			}
 		}
 	}
@@ -1010,18 +1701,113 @@ func updateListSelection(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// selectList starts creating a task in the selected list.
+func (m model) selectList(selected ListInfo) (tea.Model, tea.Cmd) {
+	m.defaultList = ""
+	m.listID = selected.ID
+	m.newTaskStatuses = nil
+	templates, err := loadTemplates()
+	if err != nil {
+		m.err = err
+		return m, tea.Quit
+	}
+	if len(templates) > 0 {
+		return m.startTemplateSelection(templates), nil
+	}
+	return m.startCreateTaskTitle(), nil
+}
+
+// matchDefaultList finds the configured default list, by ID or by name with
+// or without its folder, among the lists loaded so far.
+func (m model) matchDefaultList() (ListInfo, bool) {
+	if m.defaultList == "" || m.movingTasks {
+		return ListInfo{}, false
+	}
+	for _, item := range m.allLists {
+		l := item.(ListInfo)
+		_, name, found := strings.Cut(l.Name, " / ")
+		if !found {
+			name = l.Name
+		}
+		if l.ID == m.defaultList || strings.EqualFold(l.Name, m.defaultList) || strings.EqualFold(name, m.defaultList) {
+			return l, true
+		}
+	}
+	return ListInfo{}, false
+}
+
 // --- UPDATE & VIEW (CREATE TASK) ---
+
+// The creation wizard steps through title, description, status, assignees,
+// priority and dates, then shows a review screen. Every step goes back with esc or
+// shift+tab, and remembers what was entered. Steps opened from the review
+// screen return to it when confirmed.
+
+func isBackKey(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyEsc || msg.Type == tea.KeyShiftTab
+}
+
+// advanceCreateTask continues with next, or returns to the review screen when
+// the step was opened from there.
+func (m model) advanceCreateTask(next func(model) (model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	if m.reviewing {
+		return m.startCreateTaskReview(), nil
+	}
+	return next(m)
+}
+
+// backCreateTask returns to the step before the current one, or to the review
+// screen when the step was opened from there.
+func (m model) backCreateTask() (tea.Model, tea.Cmd) {
+	if m.reviewing && m.state != createTaskReviewView {
+		return m.startCreateTaskReview(), nil
+	}
+	switch m.state {
+	case createTaskTitleView:
+		m.newTaskTitle = m.titleInput.Value()
+		if len(m.templateList.Items()) > 0 {
+			m.state = createTaskTemplateView
+		} else {
+			m.state = listSelectionView
+		}
+	case createTaskDescView:
+		m.newTaskDesc = m.descriptionBox.Value()
+		return m.startCreateTaskTitle(), nil
+	case createTaskStatusView:
+		return m.startCreateTaskDesc(), nil
+	case createTaskAssigneeView:
+		return m.startCreateTaskStatus()
+	case createTaskPriorityView:
+		return m.startCreateTaskAssignee()
+	case taskDatesView:
+		return m.startCreateTaskPriority(), nil
+	case createTaskReviewView:
+		return m.startCreateTaskDates()
+	}
+	return m, nil
+}
+
+func (m model) startCreateTaskTitle() model {
+	m.state = createTaskTitleView
+	m.titleInput = textinput.New()
+	m.titleInput.Placeholder = "Task Title"
+	m.titleInput.SetValue(m.newTaskTitle)
+	m.titleInput.Focus()
+	return m
+}
+
 func updateCreateTaskTitle(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if isBackKey(msg) {
+			return m.backCreateTask()
+		}
 		if msg.String() == "enter" {
 			m.newTaskTitle = m.titleInput.Value()
-			m.state = createTaskDescView
-			m.descriptionBox = textarea.New()
-			m.descriptionBox.Placeholder = "Task Description"
-			m.descriptionBox.Focus()
-			return m, nil
+			return m.advanceCreateTask(func(m model) (model, tea.Cmd) {
+				return m.startCreateTaskDesc(), nil
+			})
 		}
 	}
 	m.titleInput, cmd = m.titleInput.Update(msg)
@@ -1032,18 +1818,25 @@ func (m model) viewCreateTaskTitle() string {
 	return fmt.Sprintf("Enter Task Title:\n\n%s", m.titleInput.View())
 }
 
+func (m model) startCreateTaskDesc() model {
+	m.state = createTaskDescView
+	m.descriptionBox = textarea.New()
+	m.descriptionBox.Placeholder = "Task Description"
+	m.descriptionBox.SetValue(m.newTaskDesc)
+	m.descriptionBox.Focus()
+	return m
+}
+
 func updateCreateTaskDesc(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if isBackKey(msg) {
+			return m.backCreateTask()
+		}
 		if msg.Type == tea.KeyCtrlD {
 			m.newTaskDesc = m.descriptionBox.Value()
-			m.state = createTaskStatusView
-			h, v := appStyle.GetFrameSize()
-			m.statusList = list.New([]list.Item{}, statusDelegate{}, m.width-h, m.height-v)
-			m.statusList.Title = "Select Status"
-			m.statusList.SetShowHelp(false)
-			return m, fetchStatusesCmd(m.apiToken, m.spaceID)
+			return m.advanceCreateTask(model.startCreateTaskStatus)
 		}
 	}
 	m.descriptionBox, cmd = m.descriptionBox.Update(msg)
@@ -1054,6 +1847,30 @@ func (m model) viewCreateTaskDesc() string {
 	return fmt.Sprintf("Enter Task Description (Ctrl+D to finish):\n\n%s", m.descriptionBox.View())
 }
 
+// startCreateTaskStatus shows the statuses of the list, which are loaded
+// the first time, with the chosen one selected.
+func (m model) startCreateTaskStatus() (model, tea.Cmd) {
+	m.state = createTaskStatusView
+	h, v := appStyle.GetFrameSize()
+	m.statusList = newList(m.newTaskStatuses, statusDelegate{}, m.width-h, m.height-v)
+	m.statusList.Title = "Select Status"
+	m.statusList.SetShowHelp(false)
+	if len(m.newTaskStatuses) > 0 {
+		m.selectNewTaskStatus()
+		return m, nil
+	}
+	return m, fetchStatusesCmd(m.apiToken, m.spaceID)
+}
+
+// selectNewTaskStatus moves the status list to the status chosen before.
+func (m *model) selectNewTaskStatus() {
+	for i, item := range m.statusList.Items() {
+		if m.newTaskStatus != "" && strings.EqualFold(item.(Status).Status, m.newTaskStatus) {
+			m.statusList.Select(i)
+		}
+	}
+}
+
 func updateCreateTaskStatus(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -1065,17 +1882,21 @@ func updateCreateTaskStatus(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		for i, s := range msg.Statuses {
 			items[i] = s
 		}
+		m.newTaskStatuses = items
 		m.statusList.SetItems(items)
+		m.selectNewTaskStatus()
 	case tea.KeyMsg:
+		if m.statusList.FilterState() == list.Filtering {
+			break
+		}
+		if isBackKey(msg) {
+			return m.backCreateTask()
+		}
 		if msg.String() == "enter" {
 			selected, ok := m.statusList.SelectedItem().(Status)
 			if ok {
 				m.newTaskStatus = selected.Status
-				m.state = createTaskAssigneeView
-				h, v := appStyle.GetFrameSize()
-				m.assigneeList = list.New([]list.Item{}, assigneeDelegate{selected: m.selectedAssignees}, m.width-h, m.height-v)
-				m.assigneeList.Title = "Select Assignees (space to select, enter to confirm)"
-				return m, fetchAssigneesCmd(m.apiToken, m.listID)
+				return m.advanceCreateTask(model.startCreateTaskAssignee)
 			}
 		}
 	}
@@ -1083,6 +1904,20 @@ func updateCreateTaskStatus(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m model) startCreateTaskAssignee() (model, tea.Cmd) {
+	m.state = createTaskAssigneeView
+	h, v := appStyle.GetFrameSize()
+	m.assigneeList = newList([]list.Item{}, assigneeDelegate{selected: m.selectedAssignees}, m.width-h, m.height-v)
+	m.assigneeList.Title = "Select Assignees (space to select, enter to confirm)"
+	if m.template != nil && len(m.template.Assignees) > 0 {
+		return m, tea.Batch(
+			fetchAssigneesCmd(m.apiToken, m.listID),
+			fetchUserCmd(m.apiToken),
+		)
+	}
+	return m, fetchAssigneesCmd(m.apiToken, m.listID)
+}
+
 func updateCreateTaskAssignee(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -1095,7 +1930,17 @@ func updateCreateTaskAssignee(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			items[i] = member
 		}
 		m.assigneeList.SetItems(items)
+		m.preselectTemplateAssignees()
+	case UserResponse:
+		m.currentUser = &msg.User
+		m.preselectTemplateAssignees()
 	case tea.KeyMsg:
+		if m.assigneeList.FilterState() == list.Filtering {
+			break
+		}
+		if isBackKey(msg) {
+			return m.backCreateTask()
+		}
 		switch msg.String() {
 		case " ":
 			selected, ok := m.assigneeList.SelectedItem().(Member)
@@ -1108,20 +1953,33 @@ func updateCreateTaskAssignee(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 				m.assigneeList.SetDelegate(assigneeDelegate{selected: m.selectedAssignees})
 			}
 		case "enter":
+			m.newTaskAssignees = nil
 			for id := range m.selectedAssignees {
 				m.newTaskAssignees = append(m.newTaskAssignees, id)
 			}
-			m.state = createTaskPriorityView
-			h, v := appStyle.GetFrameSize()
-			m.priorityList = list.New(priorities, priorityDelegate{}, m.width-h, m.height-v)
-			m.priorityList.Title = "Select Priority"
-			return m, nil
+			sort.Ints(m.newTaskAssignees)
+			return m.advanceCreateTask(func(m model) (model, tea.Cmd) {
+				return m.startCreateTaskPriority(), nil
+			})
 		}
 	}
 	m.assigneeList, cmd = m.assigneeList.Update(msg)
 	return m, cmd
 }
 
+func (m model) startCreateTaskPriority() model {
+	m.state = createTaskPriorityView
+	h, v := appStyle.GetFrameSize()
+	m.priorityList = newList(priorities, priorityDelegate{}, m.width-h, m.height-v)
+	m.priorityList.Title = "Select Priority"
+	for i, item := range priorities {
+		if m.newTaskPriority > 0 && item.(Priority).Value == m.newTaskPriority {
+			m.priorityList.Select(i)
+		}
+	}
+	return m
+}
+
 func updateCreateTaskPriority(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -1129,31 +1987,196 @@ func updateCreateTaskPriority(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		h, v := appStyle.GetFrameSize()
 		m.priorityList.SetSize(msg.Width-h, msg.Height-v)
 	case tea.KeyMsg:
+		if m.priorityList.FilterState() == list.Filtering {
+			break
+		}
+		if isBackKey(msg) {
+			return m.backCreateTask()
+		}
 		if msg.String() == "enter" {
 			selected, ok := m.priorityList.SelectedItem().(Priority)
 			if ok {
 				m.newTaskPriority = selected.Value
-				return m, createTaskCmd(m.apiToken, m.listID, m.newTaskTitle, m.newTaskDesc, m.newTaskStatus, m.newTaskAssignees, m.newTaskPriority)
+				return m.advanceCreateTask(model.startCreateTaskDates)
 			}
 		}
-	case string:
-		if msg == "create_success" {
-			m.state = taskCreatedView
-			m.progress = progress.New(progress.WithDefaultGradient())
-			return m, func() tea.Msg { return tickMsg(time.Now()) }
-		}
 	}
 	m.priorityList, cmd = m.priorityList.Update(msg)
 	return m, cmd
 }
 
+func (m model) startCreateTaskDates() (model, tea.Cmd) {
+	return m.startTaskDates(m.newTaskDue, m.newTaskStart, dueInput)
+}
+
+// --- UPDATE & VIEW (CREATE TASK REVIEW) ---
+
+// submitFocus is the "Create task" row below the fields of the review screen.
+const submitFocus = startFocus + 1
+
+func (m model) startCreateTaskReview() model {
+	m.state = createTaskReviewView
+	m.reviewing = true
+	return m
+}
+
+func (m model) createTaskFromWizardCmd() tea.Cmd {
+	fields := map[string]interface{}{
+		"name":        m.newTaskTitle,
+		"description": m.newTaskDesc,
+		"status":      m.newTaskStatus,
+		"assignees":   m.newTaskAssignees,
+	}
+	if m.newTaskPriority > 0 {
+		fields["priority"] = m.newTaskPriority
+	}
+	if len(m.newTaskTags) > 0 {
+		fields["tags"] = m.newTaskTags
+	}
+	if !m.newTaskDue.isZero() {
+		setDateField(fields, "due", m.newTaskDue)
+	}
+	if !m.newTaskStart.isZero() {
+		setDateField(fields, "start", m.newTaskStart)
+	}
+	return createTaskWithChecklistCmd(m.apiToken, m.listID, fields, m.newTaskChecklist)
+}
+
+func updateCreateTaskReview(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.loading {
+			return m, nil
+		}
+		if isBackKey(msg) {
+			m.reviewing = false
+			return m.backCreateTask()
+		}
+		switch msg.String() {
+		case "up", "k":
+			if m.createFocusIndex > titleFocus {
+				m.createFocusIndex--
+			}
+		case "down", "j", "tab":
+			if m.createFocusIndex < submitFocus {
+				m.createFocusIndex++
+			}
+		case "ctrl+s":
+			m.loading = true
+			return m, m.createTaskFromWizardCmd()
+		case "enter":
+			switch m.createFocusIndex {
+			case titleFocus:
+				return m.startCreateTaskTitle(), nil
+			case descriptionFocus:
+				return m.startCreateTaskDesc(), nil
+			case statusFocus:
+				return m.startCreateTaskStatus()
+			case assigneeFocus:
+				return m.startCreateTaskAssignee()
+			case priorityFocus:
+				return m.startCreateTaskPriority(), nil
+			case dueFocus:
+				return m.startTaskDates(m.newTaskDue, m.newTaskStart, dueInput)
+			case startFocus:
+				return m.startTaskDates(m.newTaskDue, m.newTaskStart, startInput)
+			case submitFocus:
+				m.loading = true
+				return m, m.createTaskFromWizardCmd()
+			}
+		}
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case Task:
+		m.loading = false
+		m.createdTask = msg
+		m.state = taskCreatedView
+		m.progress = progress.New(progress.WithDefaultGradient())
+		return m, func() tea.Msg { return tickMsg(time.Now()) }
+	}
+	return m, nil
+}
+
+func (m model) viewCreateTaskReview() string {
+	var assignees []string
+	for _, item := range m.assigneeList.Items() {
+		if member, ok := item.(Member); ok {
+			if _, selected := m.selectedAssignees[member.ID]; selected {
+				assignees = append(assignees, member.Username)
+			}
+		}
+	}
+	desc := strings.TrimSpace(m.newTaskDesc)
+	if first, _, found := strings.Cut(desc, "\n"); found {
+		desc = first + " …"
+	}
+
+	rows := []struct{ label, value string }{
+		{"Title", m.newTaskTitle},
+		{"Description", desc},
+		{"Status", m.newTaskStatus},
+		{"Assignees", strings.Join(assignees, ", ")},
+		{"Priority", priorityName(m.newTaskPriority)},
+		{"Due", m.newTaskDue.String()},
+		{"Start", m.newTaskStart.String()},
+	}
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Review New Task"))
+	b.WriteString("\n\n")
+	for i, row := range rows {
+		line := fmt.Sprintf("%-12s %s", row.label+":", orDash(row.value))
+		if i == m.createFocusIndex {
+			b.WriteString(focusedStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	if len(m.newTaskTags) > 0 {
+		b.WriteString(blurredStyle.Render(fmt.Sprintf("  %-12s %s", "Tags:", strings.Join(m.newTaskTags, ", "))) + "\n")
+	}
+	if len(m.newTaskChecklist) > 0 {
+		b.WriteString(blurredStyle.Render(fmt.Sprintf("  %-12s %d item(s)", "Checklist:", len(m.newTaskChecklist))) + "\n")
+	}
+	b.WriteString("\n")
+	if m.loading {
+		b.WriteString("  Creating task...")
+	} else if m.createFocusIndex == submitFocus {
+		b.WriteString(focusedStyle.Render("> [ Create task ]"))
+	} else {
+		b.WriteString("  [ Create task ]")
+	}
+	b.WriteString(helpStyle.Render("\n\nenter: edit field • ctrl+s: create • esc: back"))
+	return appStyle.Render(b.String())
+}
+
 // --- UPDATE & VIEW (TASK CREATED) ---
+
+// resetNewTask clears the wizard so another task can be created in the same
+// list.
+func (m model) resetNewTask() model {
+	m.newTaskTitle = ""
+	m.newTaskDesc = ""
+	m.newTaskStatus = ""
+	m.newTaskAssignees = nil
+	m.newTaskPriority = 0
+	m.newTaskDue = taskDate{}
+	m.newTaskStart = taskDate{}
+	m.newTaskTags = nil
+	m.newTaskChecklist = nil
+	m.template = nil
+	m.reviewing = false
+	m.createFocusIndex = titleFocus
+	m.selectedAssignees = make(map[int]struct{})
+	m.assigneeList = list.Model{}
+	return m
+}
+
 func updateTaskCreated(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tickMsg:
 		if m.progress.Percent() == 1.0 {
-			m.quitting = true
-			return m, tea.Quit
+			return m, nil
 		}
 		cmd := m.progress.IncrPercent(0.25)
 		return m, tea.Batch(cmd, func() tea.Msg {
@@ -1164,12 +2187,35 @@ func updateTaskCreated(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		progressModel, cmd := m.progress.Update(msg)
 		m.progress = progressModel.(progress.Model)
 		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "n":
+			m = m.resetNewTask()
+			if len(m.templateList.Items()) > 0 {
+				m.state = createTaskTemplateView
+				return m, nil
+			}
+			return m.startCreateTaskTitle(), nil
+		case "o":
+			m.isCreatingTask = false
+			m, listCmd := m.startTaskList(m.scope)
+			m, detailCmd := m.openTaskDetail(m.createdTask.ID)
+			return m, tea.Batch(listCmd, detailCmd)
+		case "q", "esc":
+			m.quitting = true
+			return m, tea.Quit
+		}
 	}
 	return m, nil
 }
 
 func (m model) viewTaskCreated() string {
-	return "\n   Task Created Successfully!\n\n" + m.progress.View() + "\n\n   Quitting..."
+	var b strings.Builder
+	b.WriteString("\n   Task Created Successfully!\n\n")
+	b.WriteString("   " + m.progress.View() + "\n\n")
+	b.WriteString("   " + m.createdTask.Name + "\n")
+	b.WriteString(helpStyle.Render("\n   n: create another in this list • o: open the created task • q: quit"))
+	return b.String()
 }
 
 // --- UPDATE & VIEW (LIST) ---
@@ -1184,9 +2230,6 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
 		}
-	case []list.Item:
-		m.loading = false
-		m.list.SetItems(msg)
 	case error:
 		m.err = msg
 		return m, tea.Quit
@@ -1194,58 +2237,81 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		if m.list.FilterState() == list.Filtering {
 			break
 		}
-		switch keypress := msg.String(); keypress {
-		case "d":
+		if updated, cmd, handled := updateListMarks(msg, m); handled {
+			return updated, cmd
+		}
+		switch {
+		case msg.String() == "esc" && m.list.FilterState() == list.Unfiltered:
+			switch {
+			case m.agenda:
+				return m.toggleAgenda()
+			case !m.calendarDay.IsZero():
+				return m.startCalendar(), nil
+			}
+			if m.scope.kind == scopeView {
+				return m.startViewSelection(), nil
+			}
+			m.state = spaceSelectionView
+			return m, nil
+		case key.Matches(msg, keys.SaveView):
+			return m.startViewName()
+		case key.Matches(msg, keys.TagFilter):
+			return m.startTagFilter(), nil
+		case key.Matches(msg, keys.Agenda):
+			return m.toggleAgenda()
+		case key.Matches(msg, keys.Calendar):
+			return m.startCalendar(), nil
+		case key.Matches(msg, keys.SwitchWorkspace):
+			return m.startProfileSelection()
+		case key.Matches(msg, keys.Sort):
+			return m.setOrder(m.order.nextSort())
+		case key.Matches(msg, keys.ReverseSort):
+			return m.setOrder(m.order.reverseSort())
+		case key.Matches(msg, keys.Group):
+			return m.setOrder(m.order.nextGroup())
+		case key.Matches(msg, keys.GoTo):
+			return m.startGotoTask(false)
+		case msg.String() == ":":
+			return m.startGotoTask(true)
+		case key.Matches(msg, keys.Duplicate):
 			selected, ok := m.list.SelectedItem().(Task)
 			if ok {
 				m.selectedTask = selected
-				m.state = deleteConfirmationView
+				m.duplicateOptions = duplicateOptions{}
+				m.state = duplicateTaskView
+				return m, nil
 			}
-		case "e":
+		case key.Matches(msg, keys.Delete):
 			selected, ok := m.list.SelectedItem().(Task)
 			if ok {
-				m.state = editTaskView
-				m.insertMode = false
 				m.selectedTask = selected
-				m.selectedStatus = selected.Status.Status
-
-				m.descriptionBox = textarea.New()
-				m.descriptionBox.SetValue(selected.Content)
-
-				m.commentBox = textinput.New()
-				m.commentBox.Placeholder = "New comment..."
-
-				m.commandInput = textinput.New()
-				m.commandInput.Prompt = ":"
-				m.commandInput.CharLimit = 5
-				return m, nil
+				m.state = deleteConfirmationView
 			}
-		case "v":
+		case key.Matches(msg, keys.Edit):
 			selected, ok := m.list.SelectedItem().(Task)
 			if ok {
-				m.state = taskDetailView
-				m.selectedTask = Task{}
-				m.comments = nil
-				m.commentsLoaded = false
-				m.viewport = viewport.New(m.width-2, m.height-2)
-				m.viewport.SetContent("Loading task details and comments...")
-				return m, tea.Batch(
-					fetchTaskDetailsCmd(m.apiToken, selected.ID),
-					fetchCommentsCmd(m.apiToken, selected.ID),
-				)
+				return m.startEditTask(selected)
+			}
+		case key.Matches(msg, keys.View):
+			selected, ok := m.list.SelectedItem().(Task)
+			if ok {
+				return m.openTaskDetail(selected.ID)
 			}
 		}
 	case string:
 		if msg == "refresh_list_success" {
+			m.stats.forget()
 			m.loading = true
 			return m, tea.Batch(
 				m.spinner.Tick,
-				fetchTasksCmd(m.apiToken, m.teamID, m.spaceID),
+				fetchTasksCmd(m.apiToken, m.teamID, m.scope),
 			)
 		}
 	}
+	previous := m.list.Index()
 	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	m.skipGroupHeader(previous)
+	return m, tea.Batch(cmd, m.fetchSelectedCounts())
 }
 
 // --- UPDATE & VIEW (STATUS UPDATE) ---
@@ -1282,6 +2348,21 @@ func updateStatusUpdate(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 }
 
 // --- UPDATE & VIEW (TASK DETAIL) ---
+
+// openTaskDetail shows a task with its comments; esc returns to the task list.
+func (m model) openTaskDetail(taskID string) (model, tea.Cmd) {
+	m.state = taskDetailView
+	m.selectedTask = Task{}
+	m.comments = nil
+	m.commentsLoaded = false
+	m.viewport = viewport.New(m.width-2, m.height-2)
+	m.viewport.SetContent("Loading task details and comments...")
+	return m, tea.Batch(
+		fetchTaskDetailsCmd(m.apiToken, taskID),
+		fetchCommentsCmd(m.apiToken, taskID),
+	)
+}
+
 func updateTaskDetail(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -1306,7 +2387,17 @@ func updateTaskDetail(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	if m.selectedTask.ID != "" {
 		var b strings.Builder
 		header := titleStyle.Render(m.selectedTask.Name)
-		content := fmt.Sprintf("Status: %s\n\n---\n\n%s", m.selectedTask.Status.Status, m.selectedTask.Content)
+		status := "Status: " + m.selectedTask.Status.Status
+		if len(m.selectedTask.Tags) > 0 {
+			status += "\nTags: " + tagBadges(m.selectedTask.Tags)
+		}
+		loc := userLocation(m.currentUser)
+		for _, d := range []struct{ label, ms string }{{"Start", m.selectedTask.StartDate}, {"Due", m.selectedTask.DueDate}} {
+			if date := taskDateOf(d.ms, loc); !date.isZero() {
+				status += "\n" + d.label + ": " + date.String()
+			}
+		}
+		content := fmt.Sprintf("%s\n\n---\n\n%s", status, m.selectedTask.Content)
 		b.WriteString(header)
 		b.WriteString("\n")
 		b.WriteString(content)
@@ -1318,13 +2409,8 @@ func updateTaskDetail(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 				b.WriteString("No comments on this task.")
 			} else {
 				for _, comment := range m.comments {
-					var commentText strings.Builder
-					for _, part := range comment.Comment {
-						commentText.WriteString(part.Text)
-					}
-					ts, _ := time.Parse(time.RFC3339, comment.Date)
-					b.WriteString(fmt.Sprintf("From: %s (%s)\n", comment.User.Username, ts.Format("2006-01-02 15:04")))
-					b.WriteString(commentText.String())
+					b.WriteString(fmt.Sprintf("From: %s (%s)\n", comment.User.Username, formatDate(comment.Time())))
+					b.WriteString(comment.Text())
 					b.WriteString("\n\n")
 				}
 			}
@@ -1340,11 +2426,42 @@ func (m model) viewTaskDetail() string {
 }
 
 // --- UPDATE & VIEW (EDIT TASK) ---
+
+// startEditTask opens the edit view of a task in normal mode.
+// startEditTask opens the edit view. Dates are read in the user's time zone,
+// so the user is loaded first when that isn't known yet.
+func (m model) startEditTask(task Task) (model, tea.Cmd) {
+	m.state = editTaskView
+	m.insertMode = false
+	m.selectedTask = task
+	m.selectedStatus = task.Status.Status
+	m.editTags = tagSet(task.Tags)
+	m.editDue = taskDateOf(task.DueDate, userLocation(m.currentUser))
+	m.editStart = taskDateOf(task.StartDate, userLocation(m.currentUser))
+
+	m.descriptionBox = textarea.New()
+	m.descriptionBox.SetValue(task.Content)
+
+	m.commentBox = textinput.New()
+	m.commentBox.Placeholder = "New comment..."
+
+	m.commandInput = textinput.New()
+	m.commandInput.Prompt = ":"
+	m.commandInput.CharLimit = 5
+	if m.currentUser == nil {
+		return m, fetchUserCmd(m.apiToken)
+	}
+	return m, nil
+}
+
 func updateEditTask(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case UserResponse:
+		m.setUser(msg.User)
+		return m, nil
 	case tea.KeyMsg:
 		if m.commandMode {
 			switch msg.Type {
@@ -1361,6 +2478,10 @@ func updateEditTask(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 						updateCmds = append(updateCmds, commentCmd)
 					}
 
+					updateCmds = append(updateCmds, m.editTagCmds()...)
+					if dates := m.editDateFields(); len(dates) > 0 {
+						updateCmds = append(updateCmds, updateTaskFieldsCmd(m.apiToken, m.selectedTask.ID, dates))
+					}
 					descChanged := m.descriptionBox.Value() != m.selectedTask.Content
 					statusChanged := m.selectedStatus != m.selectedTask.Status.Status
 					if descChanged || statusChanged {
@@ -1420,11 +2541,16 @@ func updateEditTask(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 				m.commentBox.Focus()
 			case "s":
 				m.state = statusUpdateView
-				sl := list.New([]list.Item{}, statusDelegate{}, 0, 0)
+				sl := newList([]list.Item{}, statusDelegate{}, 0, 0)
 				sl.Title = "Select new status for: " + m.selectedTask.Name
 				sl.SetShowHelp(false)
 				m.statusList = sl
 				return m, fetchStatusesCmd(m.apiToken, m.selectedTask.Space.ID)
+			case "d":
+				return m.startTaskDates(m.editDue, m.editStart, dueInput)
+			case "t":
+				m = m.startTagPicker("Tags of "+m.selectedTask.Name, m.editTags, sortedTags(m.editTags))
+				return m, fetchSpaceTagsCmd(m.apiToken, m.selectedTask.Space.ID)
 			case "q":
 				m.state = listView
 				return m, nil
@@ -1450,7 +2576,9 @@ func (m model) viewEditTask() string {
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("Editing: " + m.selectedTask.Name))
 	b.WriteString("\n\n")
-	b.WriteString(fmt.Sprintf("Status: %s\n\n", m.selectedStatus))
+	b.WriteString(fmt.Sprintf("Status: %s\n", m.selectedStatus))
+	b.WriteString(fmt.Sprintf("Tags: %s\n", tagBadges(sortedTags(m.editTags))))
+	b.WriteString(fmt.Sprintf("Due: %s • Start: %s\n\n", orDash(m.editDue.String()), orDash(m.editStart.String())))
 	b.WriteString("Description:\n")
 	b.WriteString(m.descriptionBox.View())
 	b.WriteString("\n\nAdd Comment:\n")
@@ -1461,7 +2589,7 @@ func (m model) viewEditTask() string {
 	} else if m.insertMode {
 		b.WriteString(helpStyle.Render("\n\n[INSERT MODE] esc to exit • tab to switch"))
 	} else {
-		b.WriteString(helpStyle.Render("\n\n[NORMAL MODE] i: edit desc • a: add comment • s: change status • q: back to list • :: command"))
+		b.WriteString(helpStyle.Render("\n\n[NORMAL MODE] i: edit desc • a: add comment • s: change status • t: tags • d: dates • q: back to list • :: command"))
 	}
 	return appStyle.Render(b.String())
 }
@@ -1501,7 +2629,7 @@ func updateTaskDeleted(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		if m.progress.Percent() == 1.0 {
 			m.state = listView
 			m.loading = true
-			return m, fetchTasksCmd(m.apiToken, m.teamID, m.spaceID)
+			return m, fetchTasksCmd(m.apiToken, m.teamID, m.scope)
 		}
 		cmd := m.progress.IncrPercent(0.25)
 		return m, tea.Batch(cmd, func() tea.Msg {
@@ -1521,6 +2649,20 @@ func (m model) viewTaskDeleted() string {
 }
 
 // --- MAIN (CLI) ---
+
+// configDir returns the directory holding clup's configuration files,
+// following the XDG base directory spec.
+func configDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "clup")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".config", "clup")
+}
+
 func loadConfig() {
 	home, err := os.UserHomeDir()
 	if err == nil {
@@ -1529,107 +2671,95 @@ func loadConfig() {
 	_ = godotenv.Load() // Load .env in current directory (overrides home)
 }
 
+// requireCredentials loads the configuration and exits when no credentials are
+// set, for commands that cannot fall back to the credentials form.
+func requireCredentials() (string, string) {
+	apiToken, teamID, _, err := credentials()
+	if err != nil {
+		fmt.Println("Error loading credentials:", err)
+		os.Exit(1)
+	}
+	if apiToken == "" || teamID == "" {
+		fmt.Println("API token and team ID must be set in a profile, your environment or a .env file.")
+		os.Exit(1)
+	}
+	return apiToken, teamID
+}
+
+// runTUI starts the TUI with the active profile's credentials, showing the
+// credentials form when there are none.
+func runTUI(creatingTask bool) {
+	apiToken, teamID, profileName, err := credentials()
+	if err != nil {
+		fmt.Println("Error loading credentials:", err)
+		os.Exit(1)
+	}
+	p := tea.NewProgram(newModel(apiToken, teamID, profileName, creatingTask), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Println("Error running program:", err)
+		os.Exit(1)
+	}
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "clup",
 	Short: "A TUI for ClickUp",
-	Run: func(cmd *cobra.Command, args []string) {
-		loadConfig()
-		apiToken := os.Getenv("CLICKUP_API_TOKEN")
-		teamID := os.Getenv("CLICKUP_TEAM_ID")
-		p := tea.NewProgram(newModel(apiToken, teamID, false), tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
-			fmt.Println("Error running program:", err)
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := loadSettings(); err != nil {
+			fmt.Println("Error reading config:", err)
 			os.Exit(1)
 		}
 	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runTUI(false)
+	},
 }
 
+var (
+	listFzf      bool
+	listViewName string
+)
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Find and interact with a specific task",
+	Long: `Find a task with a fuzzy finder and view, edit, delete it or change its
+status. With --fzf, the external fzf is used instead of the built-in finder.
+With --view, only the tasks of a saved view are shown, in its sort order.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		loadConfig()
-		apiToken := os.Getenv("CLICKUP_API_TOKEN")
-		teamID := os.Getenv("CLICKUP_TEAM_ID")
-		if apiToken == "" || teamID == "" {
-			fmt.Println("API token and team ID must be set in your environment or a .env file.")
-			os.Exit(1)
-		}
-
-		tasksResponse := fetchAllTasksCmd(apiToken, teamID)().(TasksResponse)
-		var tasksForFZF []string
-		taskMap := make(map[string]Task)
-		for _, task := range tasksResponse.Tasks {
-			line := fmt.Sprintf("[%s] %s", task.ID, task.Name)
-			tasksForFZF = append(tasksForFZF, line)
-			taskMap[line] = task
-		}
-
-		fzfCmd := exec.Command("fzf")
-		fzfIn, _ := fzfCmd.StdinPipe()
-		fzfOut, _ := fzfCmd.StdoutPipe()
-
-		go func() {
-			defer fzfIn.Close()
-			io.WriteString(fzfIn, strings.Join(tasksForFZF, "\n"))
-		}()
-
-		err := fzfCmd.Start()
-		if err != nil {
-			fmt.Println("Error starting fzf:", err)
-			os.Exit(1)
+		apiToken, teamID := requireCredentials()
+
+		fetch := fetchAllTasksCmd(apiToken, teamID)
+		if listViewName != "" {
+			name, ok := findView(listViewName)
+			if !ok {
+				fmt.Printf("Error: view %q does not exist.\n", listViewName)
+				os.Exit(1)
+			}
+			fetch = fetchViewTasksCmd(apiToken, teamID, name)
 		}
 
-		selectedBytes, _ := io.ReadAll(fzfOut)
-		err = fzfCmd.Wait()
-		if err != nil {
-			os.Exit(0)
-		}
-
-		selectedLine := strings.TrimSpace(string(selectedBytes))
-		if selectedLine == "" {
-			fmt.Println("No task selected.")
-			os.Exit(0)
-		}
-
-		selectedTask := taskMap[selectedLine]
-
-		fmt.Println("Selected:", selectedTask.Name)
-		fmt.Print("What do you want to do? (view/edit): ")
-		var action string
-		fmt.Scanln(&action)
-
-		initialModel := newModel(apiToken, teamID, false)
-		initialModel.selectedTask = selectedTask
-		initialModel.selectedStatus = selectedTask.Status.Status
-		initialModel.spaceID = selectedTask.Space.ID
-
-		initialModel.list = list.New([]list.Item{}, list.NewDefaultDelegate(), 1, 1)
-
-		switch action {
-		case "view":
-			initialModel.state = taskDetailView
-			initialModel.viewport = viewport.New(100, 40)
-		case "edit":
-			initialModel.state = editTaskView
-			initialModel.insertMode = false
-			initialModel.descriptionBox = textarea.New()
-			initialModel.descriptionBox.SetValue(selectedTask.Content)
-			initialModel.commentBox = textinput.New()
-			initialModel.commentBox.Placeholder = "New comment..."
-			initialModel.commandInput = textinput.New()
-			initialModel.commandInput.Prompt = ":"
-			initialModel.commandInput.CharLimit = 5
-		default:
-			fmt.Println("Invalid action.")
-			os.Exit(1)
+		var task Task
+		var action taskAction
+		if listFzf {
+			task, action = pickWithFzf(fetch)
+		} else {
+			picked, err := tea.NewProgram(newTaskPicker(fetch), tea.WithAltScreen()).Run()
+			if err != nil {
+				fmt.Println("Error running program:", err)
+				os.Exit(1)
+			}
+			picker := picked.(taskPicker)
+			if picker.err != nil {
+				fmt.Println("Error fetching tasks:", picker.err)
+				os.Exit(1)
+			}
+			task, action = picker.picked, picker.action
 		}
-
-		p := tea.NewProgram(initialModel, tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
-			fmt.Println("Error running program:", err)
-			os.Exit(1)
+		if action == actionNone {
+			return
 		}
+		runTUIAt(task.ID, action)
 	},
 }
 
@@ -1637,20 +2767,32 @@ var taskCmd = &cobra.Command{
 	Use:   "task",
 	Short: "Create a new task",
 	Run: func(cmd *cobra.Command, args []string) {
-		loadConfig()
-		apiToken := os.Getenv("CLICKUP_API_TOKEN")
-		teamID := os.Getenv("CLICKUP_TEAM_ID")
-		p := tea.NewProgram(newModel(apiToken, teamID, true), tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
-			fmt.Println("Error running program:", err)
-			os.Exit(1)
-		}
+		runTUI(true)
 	},
 }
 
 func main() {
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "workspace profile to use (defaults to $CLUP_PROFILE or the current profile)")
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(taskCmd)
+	taskCmd.AddCommand(taskUpdateCmd)
+	taskCmd.AddCommand(taskMoveCmd)
+	taskCmd.AddCommand(taskDupCmd)
+	taskCmd.AddCommand(taskCreateCmd)
+	taskCmd.AddCommand(taskTemplatesCmd)
+	taskCmd.AddCommand(taskShowCmd)
+	listCmd.Flags().BoolVar(&listFzf, "fzf", false, "use the external fzf instead of the built-in finder")
+	listCmd.Flags().StringVar(&listViewName, "view", "", "only show the tasks of this saved view")
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(gitCmd)
+	rootCmd.AddCommand(spacesCmd, foldersCmd, listsCmd)
+	rootCmd.AddCommand(viewsCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(agendaCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(serveCmd)
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
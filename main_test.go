@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// send passes msgs to the model one by one and drops the commands it returns.
+func send(m model, msgs ...tea.Msg) model {
+	for _, msg := range msgs {
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}
+	return m
+}
+
+// typed is a key press, or text typed in when s is not a key name.
+func typed(s string) tea.KeyMsg {
+	for t, name := range map[tea.KeyType]string{
+		tea.KeyEnter:    "enter",
+		tea.KeyEsc:      "esc",
+		tea.KeyShiftTab: "shift+tab",
+		tea.KeyCtrlD:    "ctrl+d",
+		tea.KeyCtrlS:    "ctrl+s",
+		tea.KeyDown:     "down",
+		tea.KeyUp:       "up",
+		tea.KeySpace:    " ",
+	} {
+		if s == name {
+			return tea.KeyMsg{Type: t}
+		}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func wizardModel() model {
+	m := newModel("pk_1", "1", "", true)
+	m.width, m.height = 80, 40
+	m.spaceID, m.listID = "100", "900"
+	return m.startCreateTaskTitle()
+}
+
+var wizardStatuses = StatusesResponse{Statuses: []Status{{Status: "open"}, {Status: "in progress"}, {Status: "review"}}}
+
+func TestCreateTaskWizardBack(t *testing.T) {
+	m := send(wizardModel(), typed("Fix login"), typed("enter"), typed("Details"), typed("ctrl+d"))
+	if m.state != createTaskStatusView {
+		t.Fatalf("state after the description = %d, want the status step", m.state)
+	}
+	m = send(m, wizardStatuses, typed("down"), typed("enter"))
+	if m.state != createTaskAssigneeView || m.newTaskStatus != "in progress" {
+		t.Fatalf("state %d with status %q, want the assignee step with in progress", m.state, m.newTaskStatus)
+	}
+
+	m = send(m, typed("esc"))
+	if m.state != createTaskStatusView {
+		t.Fatalf("back from the assignees: state %d, want the status step", m.state)
+	}
+	if s, _ := m.statusList.SelectedItem().(Status); s.Status != "in progress" {
+		t.Errorf("back at the status step: %q selected before the statuses are loaded, want in progress", s.Status)
+	}
+	m = send(m, wizardStatuses)
+	if s, _ := m.statusList.SelectedItem().(Status); s.Status != "in progress" {
+		t.Errorf("back at the status step: %q selected, want in progress", s.Status)
+	}
+
+	m = send(m, typed("shift+tab"))
+	if m.state != createTaskDescView || m.descriptionBox.Value() != "Details" {
+		t.Errorf("back at the description: state %d with %q", m.state, m.descriptionBox.Value())
+	}
+	m = send(m, typed("esc"))
+	if m.state != createTaskTitleView || m.titleInput.Value() != "Fix login" {
+		t.Errorf("back at the title: state %d with %q", m.state, m.titleInput.Value())
+	}
+	m = send(m, typed("esc"))
+	if m.state != listSelectionView {
+		t.Errorf("back from the title: state %d, want the list selection", m.state)
+	}
+}
+
+func TestCreateTaskWizardReview(t *testing.T) {
+	m := send(wizardModel(), typed("Fix login"), typed("enter"), typed("ctrl+d"), wizardStatuses, typed("enter"),
+		MembersResponse{Members: []Member{{ID: 7, Username: "ann"}, {ID: 8, Username: "bob"}}},
+		typed(" "), typed("enter"), typed("enter"), typed("tomorrow"), typed("enter"))
+	if m.state != createTaskReviewView {
+		t.Fatalf("state after the dates = %d, want the review", m.state)
+	}
+	view := m.viewCreateTaskReview()
+	for _, want := range []string{"Fix login", "open", "ann", "Urgent", "Review New Task"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("review has no %q:\n%s", want, view)
+		}
+	}
+	if m.newTaskDue.isZero() {
+		t.Errorf("due date not set from %q", "tomorrow")
+	}
+
+	// A step opened from the review returns to it, whether confirmed or not.
+	m = send(m, typed("down"), typed("down"), typed("enter"))
+	if m.state != createTaskStatusView {
+		t.Fatalf("enter on the status row: state %d, want the status step", m.state)
+	}
+	m = send(m, wizardStatuses, typed("down"), typed("down"), typed("enter"))
+	if m.state != createTaskReviewView || m.newTaskStatus != "review" {
+		t.Errorf("after changing the status: state %d with %q, want the review with review", m.state, m.newTaskStatus)
+	}
+	m = send(m, typed("enter"), typed("esc"))
+	if m.state != createTaskReviewView || m.newTaskStatus != "review" {
+		t.Errorf("after going back from the status: state %d with %q", m.state, m.newTaskStatus)
+	}
+
+	// Going back from the review walks the steps again.
+	m = send(m, typed("esc"))
+	if m.state != taskDatesView || m.reviewing {
+		t.Errorf("back from the review: state %d, reviewing %v, want the dates", m.state, m.reviewing)
+	}
+	m = send(m, typed("esc"))
+	if m.state != createTaskPriorityView {
+		t.Errorf("back from the dates: state %d, want the priority step", m.state)
+	}
+	if p, _ := m.priorityList.SelectedItem().(Priority); p.Value != m.newTaskPriority {
+		t.Errorf("priority %q selected, want %s", p.Name, priorityName(m.newTaskPriority))
+	}
+}
+
+func TestTaskCreatedFollowUps(t *testing.T) {
+	created := func() model {
+		m := wizardModel()
+		m.newTaskTitle, m.newTaskStatus, m.newTaskPriority = "Fix login", "open", 2
+		m.newTaskAssignees = []int{7}
+		m.selectedAssignees[7] = struct{}{}
+		m.reviewing = true
+		m.state = taskCreatedView
+		m.createdTask = Task{ID: "86abc", Name: "Fix login"}
+		return m
+	}
+
+	m := send(created(), typed("n"))
+	if m.state != createTaskTitleView || m.listID != "900" {
+		t.Errorf("n: state %d in list %q, want a new title in list 900", m.state, m.listID)
+	}
+	if m.newTaskTitle != "" || m.newTaskStatus != "" || m.newTaskPriority != 0 || len(m.selectedAssignees) != 0 || m.reviewing {
+		t.Errorf("n kept the last task: %q %q %d %v reviewing %v", m.newTaskTitle, m.newTaskStatus, m.newTaskPriority, m.selectedAssignees, m.reviewing)
+	}
+
+	m = send(created(), typed("o"))
+	if m.isCreatingTask || m.state != taskLoadingView && m.state != taskDetailView {
+		t.Errorf("o: state %d, creating %v, want the created task", m.state, m.isCreatingTask)
+	}
+
+	updated, cmd := created().Update(typed("q"))
+	if !updated.(model).quitting || cmd == nil {
+		t.Errorf("q did not quit")
+	}
+}
+
+func TestCheckTokenCmd(t *testing.T) {
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if !strings.HasPrefix(token, "pk_good") {
+			http.Error(w, `{"err":"Token invalid"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2/user":
+			w.Write([]byte(`{"user":{"id":1,"username":"ann"}}`))
+		case "/api/v2/team":
+			if token == "pk_good_alone" {
+				w.Write([]byte(`{"teams":[]}`))
+				return
+			}
+			w.Write([]byte(`{"teams":[{"id":"1","name":"Work"},{"id":"2","name":"Home"}]}`))
+		}
+	}))
+	tests := []struct {
+		token   string
+		teams   int
+		wantErr string
+	}{
+		{"pk_good_token", 2, ""},
+		{"pk_bad_token", 0, "invalid API token"},
+		{"pk_good_alone", 0, "no access to any workspace"},
+	}
+	for _, tt := range tests {
+		checked := checkTokenCmd(tt.token)().(tokenCheckedMsg)
+		if checked.token != tt.token || len(checked.teams) != tt.teams {
+			t.Errorf("checkTokenCmd(%q) = %q with %d teams, want %d", tt.token, checked.token, len(checked.teams), tt.teams)
+		}
+		if (checked.err == nil) != (tt.wantErr == "") || checked.err != nil && !strings.Contains(checked.err.Error(), tt.wantErr) {
+			t.Errorf("checkTokenCmd(%q): %v, want %q", tt.token, checked.err, tt.wantErr)
+		}
+		if tt.wantErr == "" && checked.user.Username != "ann" {
+			t.Errorf("checkTokenCmd(%q): user %+v", tt.token, checked.user)
+		}
+	}
+}
+
+func TestFormChecksTokenWhenTypingPauses(t *testing.T) {
+	m := newModel("", "", "", false)
+	m = send(m, typed("pk_short"))
+	m = send(m, tokenEditedMsg{seq: m.formSeq})
+	if m.loading {
+		t.Errorf("checked a token of %d characters", len(m.inputs[0].Value()))
+	}
+
+	m = send(m, typed("_and_long"))
+	typing := m.formSeq
+	m = send(m, typed("er"))
+	if m = send(m, tokenEditedMsg{seq: typing}); m.loading {
+		t.Errorf("checked the token while typing went on")
+	}
+	if m = send(m, tokenEditedMsg{seq: m.formSeq}); !m.loading {
+		t.Errorf("did not check the token once typing paused")
+	}
+
+	// A check of an older token is dropped.
+	m = send(m, tokenCheckedMsg{token: "pk_short_and_long", err: errors.New("invalid API token")})
+	if !m.loading || m.formError != nil {
+		t.Errorf("a check of an older token was shown: %v", m.formError)
+	}
+	m = send(m, tokenCheckedMsg{token: "pk_short_and_longer", err: errors.New("invalid API token")})
+	if m.loading || !strings.Contains(m.viewForm(), "✗ invalid API token") {
+		t.Errorf("the error is not shown below the token:\n%s", m.viewForm())
+	}
+	if m = send(m, typed("x")); m.formError != nil {
+		t.Errorf("the error stays while the token is edited")
+	}
+}
+
+func TestFormWorkspaces(t *testing.T) {
+	teams := []Team{{ID: "1", Name: "Work"}, {ID: "2", Name: "Home"}, {ID: "3", Name: "Club"}}
+	tests := []struct {
+		name   string
+		teams  []Team
+		teamID string // set before, as when the profile has one
+		keys   []string
+		want   string
+	}{
+		{"single workspace", teams[:1], "", []string{"enter"}, "1"},
+		{"first by default", teams, "", []string{"enter"}, "1"},
+		{"picked", teams, "", []string{"down", "down", "enter"}, "3"},
+		{"picked upwards", teams, "", []string{"down", "down", "up", "enter"}, "2"},
+		{"current one selected", teams, "2", []string{"enter"}, "2"},
+		{"stays in range", teams, "3", []string{"down", "enter"}, "3"},
+	}
+	for _, tt := range tests {
+		m := newModel("", tt.teamID, "", false)
+		m = send(m, typed("pk_good_token"), tokenCheckedMsg{token: "pk_good_token", user: User{Username: "ann"}, teams: tt.teams})
+		if m.focusIndex != workspaceFocus || !strings.Contains(m.viewForm(), "Logged in as ann") {
+			t.Errorf("%s: workspaces not focused after the check:\n%s", tt.name, m.viewForm())
+		}
+		for _, k := range tt.keys {
+			m = send(m, typed(k))
+		}
+		if m.state != spaceSelectionView || m.teamID != tt.want || m.apiToken != "pk_good_token" {
+			t.Errorf("%s: state %d with team %q and token %q, want team %q", tt.name, m.state, m.teamID, m.apiToken, tt.want)
+		}
+	}
+}
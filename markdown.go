@@ -0,0 +1,126 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- MARKDOWN ---
+
+// renderMarkdown renders the Markdown of a task description for the terminal:
+// headings, lists, checkboxes, quotes, code and inline emphasis. Paragraphs
+// are wrapped to width.
+func renderMarkdown(md string, width int) string {
+	heading := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.Accent))
+	code := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Muted))
+
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, "    "+code.Render(line))
+			continue
+		}
+		indent := strings.Repeat("  ", (len(line)-len(strings.TrimLeft(line, " \t")))/2)
+		switch {
+		case trimmed == "":
+			out = append(out, "")
+		case markdownRule.MatchString(trimmed):
+			out = append(out, code.Render(strings.Repeat("─", min(width, 40))))
+		case strings.HasPrefix(trimmed, "#"):
+			text := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			out = append(out, heading.Render(renderInline(text)))
+		case strings.HasPrefix(trimmed, ">"):
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			out = append(out, hangingIndent(code.Render("│ "), renderInline(text), width))
+		default:
+			if m := markdownItem.FindStringSubmatch(trimmed); m != nil {
+				marker, text := m[1], m[2]
+				switch {
+				case strings.HasPrefix(text, "[ ] "):
+					marker, text = "☐", text[4:]
+				case strings.HasPrefix(text, "[x] "), strings.HasPrefix(text, "[X] "):
+					marker, text = "☑", text[4:]
+				case marker == "-" || marker == "*" || marker == "+":
+					marker = "•"
+				}
+				out = append(out, hangingIndent(indent+marker+" ", renderInline(text), width))
+				continue
+			}
+			out = append(out, ansi.Wrap(renderInline(trimmed), width, ""))
+		}
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+var (
+	markdownRule   = regexp.MustCompile(`^([-*_])( ?[-*_]){2,}$`)
+	markdownItem   = regexp.MustCompile(`^([-*+]|[0-9]+[.)]) (.*)$`)
+	markdownBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownItalic = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	markdownStrike = regexp.MustCompile(`~~([^~]+)~~`)
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+)
+
+// renderInline styles emphasis, code spans and links within a line.
+func renderInline(text string) string {
+	code := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Highlight))
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = code.Render(part)
+			continue
+		}
+		part = markdownLink.ReplaceAllStringFunc(part, func(s string) string {
+			m := markdownLink.FindStringSubmatch(s)
+			if m[1] == m[2] {
+				return lipgloss.NewStyle().Underline(true).Render(m[1])
+			}
+			return lipgloss.NewStyle().Underline(true).Render(m[1]) + " " + blurredStyle.Render("("+m[2]+")")
+		})
+		part = replaceStyled(markdownBold, part, lipgloss.NewStyle().Bold(true))
+		part = replaceStyled(markdownItalic, part, lipgloss.NewStyle().Italic(true))
+		part = replaceStyled(markdownStrike, part, lipgloss.NewStyle().Strikethrough(true))
+		if i%2 == 1 {
+			part = "`" + part
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "")
+}
+
+// replaceStyled renders the first non-empty group of every match of re.
+func replaceStyled(re *regexp.Regexp, s string, style lipgloss.Style) string {
+	return re.ReplaceAllStringFunc(s, func(match string) string {
+		for _, group := range re.FindStringSubmatch(match)[1:] {
+			if group != "" {
+				return style.Render(group)
+			}
+		}
+		return match
+	})
+}
+
+// hangingIndent wraps text to width after prefix, indenting the following
+// lines to line up with the first one.
+func hangingIndent(prefix, text string, width int) string {
+	pad := lipgloss.Width(prefix)
+	wrapped := ansi.Wrap(text, max(width-pad, 10), "")
+	lines := strings.Split(wrapped, "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = prefix + lines[i]
+		} else {
+			lines[i] = strings.Repeat(" ", pad) + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
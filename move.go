@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// --- MOVE & DUPLICATE ---
+
+// taskDuplicatedMsg reports a copy of a task. When err is set, copying its
+// checklists or subtasks failed and task is the incomplete copy.
+type taskDuplicatedMsg struct {
+	task Task
+	err  error
+}
+
+// duplicateOptions selects what is copied besides the task's own fields.
+type duplicateOptions struct {
+	subtasks   bool
+	checklists bool
+}
+
+func createTaskWithFieldsCmd(apiToken, listID string, fields map[string]interface{}) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/list/%s/task", listID)
+		payload, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("create task failed: %s", string(body))
+		}
+		var task Task
+		if err := json.Unmarshal(body, &task); err != nil {
+			return err
+		}
+		return task
+	}
+}
+
+func createChecklistCmd(apiToken, taskID, name string) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/task/%s/checklist", taskID)
+		payload, err := json.Marshal(map[string]string{"name": name})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("create checklist failed: %s", string(body))
+		}
+		var checklistResponse struct {
+			Checklist Checklist `json:"checklist"`
+		}
+		if err := json.Unmarshal(body, &checklistResponse); err != nil {
+			return err
+		}
+		return checklistResponse.Checklist
+	}
+}
+
+func createChecklistItemCmd(apiToken, checklistID string, item ChecklistItem) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/checklist/%s/checklist_item", checklistID)
+		payload, err := json.Marshal(map[string]string{"name": item.Name})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("create checklist item failed: %s", string(body))
+		}
+		if !item.Resolved {
+			return nil
+		}
+		// Items are always created unresolved, so the state is copied with a
+		// second request.
+		var checklistResponse struct {
+			Checklist Checklist `json:"checklist"`
+		}
+		if err := json.Unmarshal(body, &checklistResponse); err != nil {
+			return err
+		}
+		var created ChecklistItem
+		for _, i := range checklistResponse.Checklist.Items {
+			if i.Name == item.Name {
+				created = i
+			}
+		}
+		if created.ID == "" {
+			return nil
+		}
+		url = fmt.Sprintf("https://api.clickup.com/api/v2/checklist/%s/checklist_item/%s", checklistID, created.ID)
+		req, err = http.NewRequest("PUT", url, strings.NewReader(`{"resolved": true}`))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("update checklist item failed: %s", string(body))
+		}
+		return nil
+	}
+}
+
+// copyFields builds the create payload for a copy of task. The description
+// is copied as Markdown when the task was fetched with it. The status is only
+// kept when the copy stays in the same list, since statuses differ per list.
+func copyFields(task Task, listID string) map[string]interface{} {
+	fields := map[string]interface{}{"name": task.Name}
+	if task.MarkdownContent != "" {
+		fields["markdown_description"] = task.MarkdownContent
+	} else {
+		fields["description"] = task.Content
+	}
+	var assignees []int
+	for _, a := range task.Assignees {
+		assignees = append(assignees, a.ID)
+	}
+	if len(assignees) > 0 {
+		fields["assignees"] = assignees
+	}
+	if p := taskPriorityValue(task); p > 0 {
+		fields["priority"] = p
+	}
+	var tags []string
+	for _, t := range task.Tags {
+		tags = append(tags, t.Name)
+	}
+	if len(tags) > 0 {
+		fields["tags"] = tags
+	}
+	if listID == task.List.ID && task.Status.Status != "" {
+		fields["status"] = task.Status.Status
+	}
+	return fields
+}
+
+func copyChecklists(apiToken, taskID string, checklists []Checklist) error {
+	for _, checklist := range checklists {
+		msg := createChecklistCmd(apiToken, taskID, checklist.Name)()
+		if err, ok := msg.(error); ok {
+			return err
+		}
+		created := msg.(Checklist)
+		for _, item := range checklist.Items {
+			if err, ok := createChecklistItemCmd(apiToken, created.ID, item)().(error); ok {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// duplicateTaskCmd copies a task into listID (its own list when empty).
+func duplicateTaskCmd(apiToken, taskID, listID string, opts duplicateOptions) tea.Cmd {
+	return func() tea.Msg {
+		msg := fetchTaskRefCmd(apiToken, "", taskID)()
+		if err, ok := msg.(error); ok {
+			return err
+		}
+		source := msg.(Task)
+		if listID == "" {
+			listID = source.List.ID
+		}
+
+		msg = createTaskWithFieldsCmd(apiToken, listID, copyFields(source, listID))()
+		if err, ok := msg.(error); ok {
+			return err
+		}
+		copied := msg.(Task)
+		return taskDuplicatedMsg{task: copied, err: copyContents(apiToken, listID, source, copied.ID, opts)}
+	}
+}
+
+// copyContents copies the checklists and subtasks of source into the copy
+// with ID copyID, as selected by opts.
+func copyContents(apiToken, listID string, source Task, copyID string, opts duplicateOptions) error {
+	if opts.checklists {
+		if err := copyChecklists(apiToken, copyID, source.Checklists); err != nil {
+			return err
+		}
+	}
+	if !opts.subtasks {
+		return nil
+	}
+	for _, subtask := range source.Subtasks {
+		// Subtasks embedded in the parent lack checklists and their Markdown
+		// description, so fetch them.
+		msg := fetchTaskRefCmd(apiToken, "", subtask.ID)()
+		if err, ok := msg.(error); ok {
+			return err
+		}
+		subtask = msg.(Task)
+		fields := copyFields(subtask, listID)
+		fields["parent"] = copyID
+		msg = createTaskWithFieldsCmd(apiToken, listID, fields)()
+		if err, ok := msg.(error); ok {
+			return err
+		}
+		if opts.checklists {
+			if err := copyChecklists(apiToken, msg.(Task).ID, subtask.Checklists); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// --- UPDATE & VIEW (DUPLICATE TASK) ---
+func updateDuplicateTask(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	case tea.KeyMsg:
+		if m.loading {
+			return m, nil
+		}
+		switch msg.String() {
+		case "s":
+			m.duplicateOptions.subtasks = !m.duplicateOptions.subtasks
+		case "c":
+			m.duplicateOptions.checklists = !m.duplicateOptions.checklists
+		case "enter", "y":
+			m.loading = true
+			return m, tea.Batch(
+				m.spinner.Tick,
+				duplicateTaskCmd(m.apiToken, m.selectedTask.ID, "", m.duplicateOptions),
+			)
+		case "esc", "n", "q":
+			m.state = listView
+		}
+	case taskDuplicatedMsg:
+		m.state = listView
+		m.loading = true
+		status := statusMessageStyle("Duplicated " + msg.task.Name)
+		if msg.err != nil {
+			status = failureStyle.Render(fmt.Sprintf("Duplicated %s only partly: %v", msg.task.Name, msg.err))
+		}
+		statusCmd := m.list.NewStatusMessage(status)
+		return m, tea.Batch(
+			statusCmd,
+			m.spinner.Tick,
+			fetchTasksCmd(m.apiToken, m.teamID, m.scope),
+		)
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) viewDuplicateTask() string {
+	if m.loading {
+		return fmt.Sprintf("\n\n   %s Duplicating '%s'...\n\n", m.spinner.View(), m.selectedTask.Name)
+	}
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Duplicate: " + m.selectedTask.Name))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("%s include subtasks (s)\n", check(m.duplicateOptions.subtasks)))
+	b.WriteString(fmt.Sprintf("%s include checklists (c)\n", check(m.duplicateOptions.checklists)))
+	b.WriteString(helpStyle.Render("\nenter to duplicate • esc to cancel"))
+	return appStyle.Render(b.String())
+}
+
+// --- MOVE & DUPLICATE (CLI) ---
+
+var (
+	dupListID     string
+	dupSubtasks   bool
+	dupChecklists bool
+)
+
+var taskMoveCmd = &cobra.Command{
+	Use:   "move <task> <list-id>",
+	Short: "Move a task to another list",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		taskID, err := resolveTaskID(apiToken, teamID, args[0])
+		if err != nil {
+			fmt.Println("Error finding task:", err)
+			os.Exit(1)
+		}
+		if err, ok := moveTaskCmd(apiToken, teamID, taskID, args[1])().(error); ok {
+			fmt.Println("Error moving task:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Moved task %s to list %s.\n", args[0], args[1])
+	},
+}
+
+var taskDupCmd = &cobra.Command{
+	Use:     "dup <task>",
+	Aliases: []string{"duplicate"},
+	Short:   "Duplicate a task",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		taskID, err := resolveTaskID(apiToken, teamID, args[0])
+		if err != nil {
+			fmt.Println("Error finding task:", err)
+			os.Exit(1)
+		}
+		opts := duplicateOptions{subtasks: dupSubtasks, checklists: dupChecklists}
+		switch msg := duplicateTaskCmd(apiToken, taskID, dupListID, opts)().(type) {
+		case error:
+			fmt.Println("Error duplicating task:", msg)
+			os.Exit(1)
+		case taskDuplicatedMsg:
+			if msg.err != nil {
+				fmt.Println("Error duplicating task:", msg.err)
+				fmt.Printf("The incomplete copy is %s %s\n", msg.task.ID, msg.task.URL)
+				os.Exit(1)
+			}
+			fmt.Printf("Duplicated task %s as %s %s\n", args[0], msg.task.ID, msg.task.URL)
+		}
+	},
+}
+
+func init() {
+	taskDupCmd.Flags().StringVar(&dupListID, "list", "", "list to create the copy in (defaults to the task's list)")
+	taskDupCmd.Flags().BoolVar(&dupSubtasks, "subtasks", false, "also copy subtasks")
+	taskDupCmd.Flags().BoolVar(&dupChecklists, "checklists", false, "also copy checklists")
+}
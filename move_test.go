@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCopyFields(t *testing.T) {
+	task := Task{Name: "Fix login", Content: "Fix *login*", MarkdownContent: "Fix **login**"}
+	task.List.ID = "900"
+	task.Status.Status = "in progress"
+	task.Priority = &TaskPriority{ID: "2"}
+	task.Assignees = []Member{{ID: 1}, {ID: 2}}
+	task.Tags = []Tag{{Name: "bug"}}
+
+	plain := Task{Name: "Plain", Content: "Just text"}
+	plain.List.ID = "900"
+
+	tests := []struct {
+		name   string
+		task   Task
+		listID string
+		want   map[string]interface{}
+	}{
+		{"same list", task, "900", map[string]interface{}{
+			"name":                 "Fix login",
+			"markdown_description": "Fix **login**",
+			"assignees":            []int{1, 2},
+			"priority":             2,
+			"tags":                 []string{"bug"},
+			"status":               "in progress",
+		}},
+		{"other list", task, "901", map[string]interface{}{
+			"name":                 "Fix login",
+			"markdown_description": "Fix **login**",
+			"assignees":            []int{1, 2},
+			"priority":             2,
+			"tags":                 []string{"bug"},
+		}},
+		{"without Markdown", plain, "900", map[string]interface{}{
+			"name":        "Plain",
+			"description": "Just text",
+		}},
+	}
+	for _, tt := range tests {
+		if got := copyFields(tt.task, tt.listID); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: copyFields = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDuplicateKeepsIncompleteCopy(t *testing.T) {
+	var created map[string]interface{}
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v2/task/src":
+			w.Write([]byte(`{"id":"src","name":"Fix login","markdown_description":"**bold**","list":{"id":"900"},
+				"checklists":[{"id":"c1","name":"Steps","items":[{"name":"one"}]}]}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v2/list/900/task":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"id":"copy","name":"Fix login","url":"https://app.clickup.com/t/copy"}`))
+		default:
+			http.Error(w, `{"err":"unavailable"}`, http.StatusInternalServerError)
+		}
+	}))
+
+	msg := duplicateTaskCmd("pk_1", "src", "", duplicateOptions{checklists: true})()
+	dup, ok := msg.(taskDuplicatedMsg)
+	if !ok {
+		t.Fatalf("duplicateTaskCmd = %v, want a taskDuplicatedMsg", msg)
+	}
+	if dup.task.ID != "copy" || dup.err == nil || !strings.Contains(dup.err.Error(), "create checklist failed") {
+		t.Errorf("duplicate = %s, %v, want the copy with a checklist error", dup.task.ID, dup.err)
+	}
+	if created["markdown_description"] != "**bold**" {
+		t.Errorf("created the copy with %v, want the Markdown description", created)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// --- OAUTH ---
+
+// oauthSettings configures `clup auth login --oauth`. The client is a ClickUp
+// OAuth app whose redirect URL points at 127.0.0.1.
+type oauthSettings struct {
+	ClientID     string `yaml:"client_id,omitempty"`
+	ClientSecret string `yaml:"client_secret,omitempty"`
+	AuthorizeURL string `yaml:"authorize_url,omitempty"`
+	TokenURL     string `yaml:"token_url,omitempty"`
+	RedirectPort int    `yaml:"redirect_port,omitempty"`
+}
+
+const (
+	defaultAuthorizeURL = "https://app.clickup.com/api"
+	defaultTokenURL     = "https://api.clickup.com/api/v2/oauth/token"
+	oauthTimeout        = 5 * time.Minute
+	bearerPrefix        = "Bearer "
+)
+
+// tokenKind describes a token for `clup auth status`. Tokens are stored as
+// they are sent in the Authorization header, so OAuth access tokens keep
+// their token type ("Bearer <token>").
+func tokenKind(apiToken string) string {
+	if strings.HasPrefix(apiToken, bearerPrefix) {
+		return "OAuth"
+	}
+	return "personal"
+}
+
+// openBrowser opens url in the default browser.
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// oauthLogin runs the authorization code flow: it listens for the redirect on
+// a loopback address, sends the user to the authorize page and exchanges the
+// returned code for an access token, which is returned with its type.
+func oauthLogin(o oauthSettings, launchBrowser bool) (string, error) {
+	if o.ClientID == "" || o.ClientSecret == "" {
+		return "", errors.New("an OAuth client ID and secret are required (--client-id/--client-secret, oauth.client_id/oauth.client_secret or CLUP_OAUTH_CLIENT_ID/CLUP_OAUTH_CLIENT_SECRET)")
+	}
+	if o.AuthorizeURL == "" {
+		o.AuthorizeURL = defaultAuthorizeURL
+	}
+	if o.TokenURL == "" {
+		o.TokenURL = defaultTokenURL
+	}
+	state, err := randomState()
+	if err != nil {
+		return "", err
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", o.RedirectPort))
+	if err != nil {
+		return "", fmt.Errorf("cannot listen for the OAuth redirect: %v", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res result
+		switch {
+		case q.Get("state") != state:
+			res.err = errors.New("OAuth state mismatch")
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("authorization denied: %s", q.Get("error"))
+		case q.Get("code") == "":
+			res.err = errors.New("no authorization code in the redirect")
+		default:
+			res.code = q.Get("code")
+		}
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "clup is authorized. You can close this window.")
+		}
+		select {
+		case results <- res:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	authURL, err := neturl.Parse(o.AuthorizeURL)
+	if err != nil {
+		return "", err
+	}
+	q := authURL.Query()
+	q.Set("client_id", o.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	authURL.RawQuery = q.Encode()
+
+	fmt.Printf("Open this URL to authorize clup:\n\n  %s\n\n", authURL)
+	if launchBrowser {
+		if err := openBrowser(authURL.String()); err != nil {
+			fmt.Fprintln(os.Stderr, "Could not open a browser:", err)
+		}
+	}
+	fmt.Println("Waiting for the authorization...")
+
+	var res result
+	select {
+	case res = <-results:
+	case <-time.After(oauthTimeout):
+		return "", errors.New("timed out waiting for the authorization")
+	}
+	if res.err != nil {
+		return "", res.err
+	}
+	return exchangeOAuthCode(o, res.code)
+}
+
+// exchangeOAuthCode trades an authorization code for an access token, and
+// returns it as the Authorization header to send.
+func exchangeOAuthCode(o oauthSettings, code string) (string, error) {
+	tokenURL, err := neturl.Parse(o.TokenURL)
+	if err != nil {
+		return "", err
+	}
+	q := tokenURL.Query()
+	q.Set("client_id", o.ClientID)
+	q.Set("client_secret", o.ClientSecret)
+	q.Set("code", code)
+	tokenURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("POST", tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token exchange failed: %s", string(body))
+	}
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", err
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", fmt.Errorf("token exchange failed: no access token in %s", string(body))
+	}
+	if tokenResponse.TokenType != "" && !strings.EqualFold(tokenResponse.TokenType, "bearer") {
+		return "", fmt.Errorf("token exchange failed: unsupported token type %q", tokenResponse.TokenType)
+	}
+	return bearerPrefix + tokenResponse.AccessToken, nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// chooseTeam picks the workspace for a new token: the only one, or the one
+// the user selects from a numbered list.
+func chooseTeam(apiToken string, pick func(label string) string) (string, error) {
+	var teams []Team
+	switch msg := fetchTeamsCmd(apiToken)().(type) {
+	case error:
+		return "", msg
+	case TeamsResponse:
+		teams = msg.Teams
+	}
+	switch len(teams) {
+	case 0:
+		return "", errors.New("the token has no access to any workspace")
+	case 1:
+		fmt.Printf("Using workspace %s (%s).\n", teams[0].Name, teams[0].ID)
+		return teams[0].ID, nil
+	}
+	for i, t := range teams {
+		fmt.Printf("  %d) %s (%s)\n", i+1, t.Name, t.ID)
+	}
+	answer := pick("Workspace: ")
+	for i, t := range teams {
+		if answer == fmt.Sprint(i+1) || answer == t.ID || strings.EqualFold(answer, t.Name) {
+			return t.ID, nil
+		}
+	}
+	return "", fmt.Errorf("unknown workspace %q", answer)
+}
+
+// oauthClient fills in the OAuth app from the flags and environment, which
+// take precedence over the config file.
+func oauthClient(o oauthSettings) oauthSettings {
+	if loginClientID != "" {
+		o.ClientID = loginClientID
+	} else if id := os.Getenv("CLUP_OAUTH_CLIENT_ID"); id != "" {
+		o.ClientID = id
+	}
+	if loginClientSecret != "" {
+		o.ClientSecret = loginClientSecret
+	} else if secret := os.Getenv("CLUP_OAUTH_CLIENT_SECRET"); secret != "" {
+		o.ClientSecret = secret
+	}
+	return o
+}
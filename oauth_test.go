@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"testing"
+)
+
+func TestOAuthLogin(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != "POST" || q.Get("client_id") != "id" || q.Get("client_secret") != "secret" || q.Get("code") != "abc" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"access_token":"tok","token_type":"bearer"}`))
+	}))
+	defer tokenServer.Close()
+
+	// The browser is the user approving the app right away.
+	defer func(open func(string) error) { openBrowser = open }(openBrowser)
+	openBrowser = func(authURL string) error {
+		u, err := neturl.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		redirect, err := neturl.Parse(q.Get("redirect_uri"))
+		if err != nil {
+			return err
+		}
+		if redirect.Hostname() != "127.0.0.1" || q.Get("client_id") != "id" {
+			t.Errorf("authorize URL %s, want a 127.0.0.1 redirect for client id", authURL)
+		}
+		redirect.RawQuery = neturl.Values{"code": {"abc"}, "state": {q.Get("state")}}.Encode()
+		go func() {
+			resp, err := http.Get(redirect.String())
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	o := oauthSettings{ClientID: "id", ClientSecret: "secret", AuthorizeURL: "https://example.com/authorize", TokenURL: tokenServer.URL}
+	token, err := oauthLogin(o, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "Bearer tok" {
+		t.Errorf("token = %q, want %q", token, "Bearer tok")
+	}
+	if tokenKind(token) != "OAuth" || tokenKind("pk_1") != "personal" {
+		t.Errorf("tokenKind = %q for OAuth, %q for a personal token", tokenKind(token), tokenKind("pk_1"))
+	}
+}
+
+func TestOAuthLoginStateMismatch(t *testing.T) {
+	defer func(open func(string) error) { openBrowser = open }(openBrowser)
+	openBrowser = func(authURL string) error {
+		u, _ := neturl.Parse(authURL)
+		go func() {
+			resp, err := http.Get(u.Query().Get("redirect_uri") + "?code=abc&state=forged")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+	o := oauthSettings{ClientID: "id", ClientSecret: "secret", TokenURL: "http://127.0.0.1:1/token"}
+	if _, err := oauthLogin(o, true); err == nil || !strings.Contains(err.Error(), "state mismatch") {
+		t.Errorf("oauthLogin = %v, want a state mismatch", err)
+	}
+}
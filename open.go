@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- OPENING A TASK ---
+
+// Commands such as `clup list` and `clup git current` start the TUI at a
+// task. The task is loaded first, then the task list of its space is set up
+// behind it, so leaving the task returns to a real list like in a normal
+// session.
+
+// taskAction is what the TUI opens a task with.
+type taskAction int
+
+const (
+	actionNone taskAction = iota
+	actionView
+	actionEdit
+	actionStatus
+	actionDelete
+)
+
+// startAtTask makes the TUI open at a task instead of the space selection.
+// The task can be given in any form parseTaskRef accepts.
+func (m model) startAtTask(ref string, action taskAction) model {
+	m.state = taskLoadingView
+	m.openTaskRef = ref
+	m.openAction = action
+	m.spinner = spinner.New()
+	return m
+}
+
+func updateTaskLoading(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case Task:
+		m.selectedTask = msg
+		m.spaceID = msg.Space.ID
+		return m, fetchSpacesCmd(m.apiToken, m.teamID)
+	case SpacesResponse:
+		scope := taskScope{kind: scopeSpace, id: m.spaceID, name: m.spaceID}
+		for _, s := range msg.Spaces {
+			if s.ID == m.spaceID {
+				scope.name = s.Name
+			}
+		}
+		m.tree.spaces = msg.Spaces
+		items := m.tree.items()
+		m.spaceList.SetItems(items)
+		m.selectTreeNode(items, nodeKey(scopeSpace, m.spaceID))
+		m, listCmd := m.startTaskList(scope)
+		m, actionCmd := m.openTaskWith(m.selectedTask, m.openAction)
+		return m, tea.Batch(listCmd, actionCmd)
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// openTaskWith shows task in the view for action, on top of the task list.
+func (m model) openTaskWith(task Task, action taskAction) (model, tea.Cmd) {
+	switch action {
+	case actionEdit:
+		return m.startEditTask(task)
+	case actionStatus:
+		h, v := appStyle.GetFrameSize()
+		m.selectedTask = task
+		m.bulkTasks = []Task{task}
+		m.state = bulkStatusView
+		m.statusList = newList([]list.Item{}, statusDelegate{}, m.width-h, m.height-v)
+		m.statusList.Title = "Set status of " + task.Name
+		m.statusList.SetShowHelp(false)
+		return m, fetchStatusesCmd(m.apiToken, m.spaceID)
+	case actionDelete:
+		m.selectedTask = task
+		m.state = deleteConfirmationView
+		return m, nil
+	}
+	return m.openTaskDetail(task.ID)
+}
+
+func (m model) viewTaskLoading() string {
+	return fmt.Sprintf("\n\n   %s Loading task...\n\n", m.spinner.View())
+}
+
+// runTUIAt starts the TUI at a task with the active profile's credentials.
+func runTUIAt(ref string, action taskAction) {
+	apiToken, teamID, profileName, err := credentials()
+	if err != nil {
+		fmt.Println("Error loading credentials:", err)
+		os.Exit(1)
+	}
+	if apiToken == "" || teamID == "" {
+		fmt.Println("API token and team ID must be set in a profile, your environment or a .env file.")
+		os.Exit(1)
+	}
+	m := newModel(apiToken, teamID, profileName, false).startAtTask(ref, action)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Println("Error running program:", err)
+		os.Exit(1)
+	}
+}
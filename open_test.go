@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// useWorkspace serves one task in one of two spaces.
+func useWorkspace(t *testing.T) {
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/task/86abc":
+			w.Write([]byte(`{"id":"86abc","name":"Fix login","space":{"id":"200"}}`))
+		case "/api/v2/team/1/space":
+			w.Write([]byte(`{"spaces":[{"id":"100","name":"Engineering"},{"id":"200","name":"Ops"}]}`))
+		default:
+			http.Error(w, `{"err":"Task not found"}`, http.StatusNotFound)
+		}
+	}))
+}
+
+// loadTask starts the TUI at ref and runs the loading steps: the task, then
+// the spaces. It stops early when a step fails.
+func loadTask(t *testing.T, ref string, action taskAction) model {
+	m := newModel("pk_1", "1", "", false).startAtTask(ref, action)
+	m.width, m.height = 80, 40
+	if m.state != taskLoadingView || !strings.Contains(m.View(), "Loading task") {
+		t.Fatalf("startAtTask: state %d", m.state)
+	}
+	msg := fetchTaskRefCmd(m.apiToken, m.teamID, m.openTaskRef)()
+	for i := 0; i < 2 && m.state == taskLoadingView && m.err == nil; i++ {
+		var cmd tea.Cmd
+		var updated tea.Model
+		updated, cmd = m.Update(msg)
+		m = updated.(model)
+		if cmd != nil && m.state == taskLoadingView {
+			msg = cmd()
+		}
+	}
+	return m
+}
+
+func TestOpenTask(t *testing.T) {
+	useWorkspace(t)
+	tests := []struct {
+		action taskAction
+		state  viewState
+	}{
+		{actionView, taskDetailView},
+		{actionEdit, editTaskView},
+		{actionStatus, bulkStatusView},
+		{actionDelete, deleteConfirmationView},
+	}
+	for _, tt := range tests {
+		m := loadTask(t, "https://app.clickup.com/t/86abc", tt.action)
+		if m.err != nil {
+			t.Fatalf("action %d: %v", tt.action, m.err)
+		}
+		if m.state != tt.state {
+			t.Errorf("action %d: state %d, want %d", tt.action, m.state, tt.state)
+		}
+		// The list behind the task is its space.
+		if m.spaceID != "200" || m.scope.kind != scopeSpace || m.scope.id != "200" || m.scope.name != "Ops" {
+			t.Errorf("action %d: list of space %q, scope %+v, want Ops", tt.action, m.spaceID, m.scope)
+		}
+		if node, ok := m.spaceList.SelectedItem().(treeNode); !ok || node.spaceID != "200" {
+			t.Errorf("action %d: tree selection %+v, want the space of the task", tt.action, m.spaceList.SelectedItem())
+		}
+		if tt.action != actionView && m.selectedTask.ID != "86abc" {
+			t.Errorf("action %d: selected task %q", tt.action, m.selectedTask.ID)
+		}
+	}
+}
+
+func TestOpenTaskErrors(t *testing.T) {
+	useWorkspace(t)
+	for _, ref := range []string{"https://app.clickup.com/v/l/abc", "gone"} {
+		m := loadTask(t, ref, actionView)
+		if m.err == nil || m.state != taskLoadingView {
+			t.Errorf("%q: state %d, error %v, want an error while loading", ref, m.state, m.err)
+			continue
+		}
+		if !strings.Contains(m.View(), "An error occurred") {
+			t.Errorf("%q: the error is not shown:\n%s", ref, m.View())
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// --- TASK PICKER ---
+
+// pickerSource lets the fuzzy matcher search task names.
+type pickerSource []Task
+
+func (s pickerSource) String(i int) string { return s[i].Name }
+func (s pickerSource) Len() int            { return len(s) }
+
+// taskPicker is a fuzzy finder over the tasks of the workspace or of a saved
+// view, with a preview of the task under the cursor.
+type taskPicker struct {
+	fetch         tea.Cmd
+	tasks         []Task
+	matches       fuzzy.Matches
+	cursor        int
+	query         textinput.Model
+	spinner       spinner.Model
+	loading       bool
+	width, height int
+	err           error
+	picked        Task
+	action        taskAction
+}
+
+// newTaskPicker searches the tasks loaded by fetch, which returns a
+// TasksResponse.
+func newTaskPicker(fetch tea.Cmd) taskPicker {
+	q := textinput.New()
+	q.Prompt = "> "
+	q.Placeholder = "search tasks"
+	q.Width = 40
+	q.PromptStyle = focusedStyle
+	q.Cursor.Style = cursorStyle
+	q.Focus()
+	return taskPicker{fetch: fetch, query: q, spinner: spinner.New(), loading: true}
+}
+
+func (p taskPicker) Init() tea.Cmd {
+	return tea.Batch(p.fetch, p.spinner.Tick, textinput.Blink)
+}
+
+// filter matches the tasks against the query. An empty query keeps the
+// tasks in the order ClickUp returned them.
+func (p *taskPicker) filter() {
+	query := strings.TrimSpace(p.query.Value())
+	if query == "" {
+		p.matches = make(fuzzy.Matches, len(p.tasks))
+		for i := range p.tasks {
+			p.matches[i] = fuzzy.Match{Str: pickerSource(p.tasks).String(i), Index: i}
+		}
+	} else {
+		p.matches = fuzzy.FindFrom(query, pickerSource(p.tasks))
+	}
+	if p.cursor >= len(p.matches) {
+		p.cursor = max(len(p.matches)-1, 0)
+	}
+}
+
+func (p taskPicker) selected() (Task, bool) {
+	if len(p.matches) == 0 {
+		return Task{}, false
+	}
+	return p.tasks[p.matches[p.cursor].Index], true
+}
+
+func (p taskPicker) pick(action taskAction) (tea.Model, tea.Cmd) {
+	task, ok := p.selected()
+	if !ok {
+		return p, nil
+	}
+	p.picked = task
+	p.action = action
+	return p, tea.Quit
+}
+
+func (p taskPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+		return p, nil
+	case spinner.TickMsg:
+		if p.loading {
+			p.spinner, cmd = p.spinner.Update(msg)
+		}
+		return p, cmd
+	case TasksResponse:
+		p.loading = false
+		p.tasks = msg.Tasks
+		p.filter()
+		return p, nil
+	case error:
+		p.err = msg
+		return p, tea.Quit
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return p, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+		case "down", "ctrl+n", "ctrl+j":
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+			}
+			return p, nil
+		case "enter":
+			return p.pick(actionView)
+		}
+		if !p.query.Focused() {
+			switch {
+			case msg.String() == "k":
+				if p.cursor > 0 {
+					p.cursor--
+				}
+			case msg.String() == "j":
+				if p.cursor < len(p.matches)-1 {
+					p.cursor++
+				}
+			case msg.String() == "/" || msg.String() == "tab":
+				p.query.Focus()
+				return p, textinput.Blink
+			case msg.String() == "q" || msg.String() == "esc":
+				return p, tea.Quit
+			case key.Matches(msg, keys.View):
+				return p.pick(actionView)
+			case key.Matches(msg, keys.Edit):
+				return p.pick(actionEdit)
+			case key.Matches(msg, keys.Status):
+				return p.pick(actionStatus)
+			case key.Matches(msg, keys.Delete):
+				return p.pick(actionDelete)
+			}
+			return p, nil
+		}
+		switch msg.String() {
+		case "esc", "tab":
+			p.query.Blur()
+			return p, nil
+		}
+	}
+	p.query, cmd = p.query.Update(msg)
+	p.filter()
+	return p, cmd
+}
+
+func (p taskPicker) View() string {
+	if p.loading {
+		return fmt.Sprintf("\n  %s Loading tasks...\n", p.spinner.View())
+	}
+	width := p.width - 4
+	listWidth := width
+	showPreview := width >= 90
+	if showPreview {
+		listWidth = width / 2
+	}
+	rows := max(p.height-6, 1)
+
+	var b strings.Builder
+	b.WriteString(p.query.View())
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("  %d/%d", len(p.matches), len(p.tasks))))
+	b.WriteString("\n")
+	start := 0
+	if p.cursor >= rows {
+		start = p.cursor - rows + 1
+	}
+	for i := start; i < len(p.matches) && i < start+rows; i++ {
+		line := highlightMatch(p.matches[i], p.tasks[p.matches[i].Index].List.Name, listWidth-2)
+		if i == p.cursor {
+			b.WriteString(focusedStyle.Render("▌ ") + line)
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	results := lipgloss.NewStyle().Width(listWidth).Height(rows + 2).Render(b.String())
+
+	var help string
+	if p.query.Focused() {
+		help = "enter: view • ↑/↓: move • tab: actions • ctrl+c: quit"
+	} else {
+		help = fmt.Sprintf("%s: view • %s: edit • %s: status • %s: delete • /: search • q: quit",
+			keys.View.Help().Key, keys.Edit.Help().Key, keys.Status.Help().Key, keys.Delete.Help().Key)
+	}
+	body := results
+	if task, ok := p.selected(); ok && showPreview {
+		preview := lipgloss.NewStyle().
+			Width(width-listWidth-3).
+			Height(rows+1).
+			MaxHeight(rows+2).
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color(theme.Muted)).
+			PaddingLeft(1).
+			Render(taskPreview(task, width-listWidth-4))
+		body = lipgloss.JoinHorizontal(lipgloss.Top, results, preview)
+	}
+	return appStyle.Render(body + "\n" + helpStyle.Render(help))
+}
+
+// highlightMatch renders a matched task name with the matched characters in
+// the highlight color, followed by its list name dimmed.
+func highlightMatch(match fuzzy.Match, listName string, width int) string {
+	matched := make(map[int]bool, len(match.MatchedIndexes))
+	for _, i := range match.MatchedIndexes {
+		matched[i] = true
+	}
+	var b strings.Builder
+	n := 0
+	for i, r := range match.Str {
+		if n >= width {
+			return b.String() + "…"
+		}
+		if matched[i] {
+			b.WriteString(focusedStyle.Bold(true).Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+		n++
+	}
+	if listName != "" && n+len(listName)+3 <= width {
+		b.WriteString(blurredStyle.Render("  " + listName))
+	}
+	return b.String()
+}
+
+// taskPreview summarizes a task for the picker's preview pane.
+func taskPreview(task Task, width int) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(task.Name))
+	b.WriteString("\n\n")
+	field := func(label, value string) {
+		if value != "" {
+			b.WriteString(blurredStyle.Render(fmt.Sprintf("%-9s", label)) + value + "\n")
+		}
+	}
+	field("ID", task.ID)
+	field("Status", task.Status.Status)
+	field("List", taskLocation(task))
+	var assignees []string
+	for _, a := range task.Assignees {
+		assignees = append(assignees, a.Username)
+	}
+	field("Assigned", strings.Join(assignees, ", "))
+	if task.Priority != nil {
+		field("Priority", task.Priority.Priority)
+	}
+	if t, ok := msTime(task.DueDate); ok {
+		field("Due", formatDate(t))
+	}
+	var tags []string
+	for _, t := range task.Tags {
+		tags = append(tags, t.Name)
+	}
+	field("Tags", strings.Join(tags, ", "))
+	if desc := strings.TrimSpace(task.Content); desc != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Width(width).Render(desc))
+	}
+	return b.String()
+}
+
+// pickWithFzf lets the user pick a task with the external fzf, previewed with
+// `clup task show`. ctrl-e, ctrl-s and ctrl-d pick the task for editing,
+// a status change or deletion.
+func pickWithFzf(fetch tea.Cmd) (Task, taskAction) {
+	var tasks []Task
+	switch msg := fetch().(type) {
+	case error:
+		fmt.Println("Error fetching tasks:", msg)
+		os.Exit(1)
+	case TasksResponse:
+		tasks = msg.Tasks
+	}
+	taskMap := make(map[string]Task)
+	var lines []string
+	for _, task := range tasks {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s", task.ID, task.Name, task.List.Name))
+		taskMap[task.ID] = task
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		self = "clup"
+	}
+	preview := fmt.Sprintf("%q task show {1}", self)
+	if profileFlag != "" {
+		preview = fmt.Sprintf("%q --profile %q task show {1}", self, profileFlag)
+	}
+	fzfCmd := exec.Command("fzf",
+		"--delimiter", "\t",
+		"--with-nth", "2..",
+		"--preview", preview,
+		"--expect", "ctrl-e,ctrl-s,ctrl-d",
+		"--header", "enter: view • ctrl-e: edit • ctrl-s: status • ctrl-d: delete",
+	)
+	fzfCmd.Stdin = strings.NewReader(strings.Join(lines, "\n"))
+	fzfCmd.Stderr = os.Stderr
+	out, err := fzfCmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return Task{}, actionNone
+		}
+		fmt.Println("Error starting fzf:", err)
+		os.Exit(1)
+	}
+
+	result := strings.SplitN(strings.TrimRight(string(out), "\n"), "\n", 2)
+	if len(result) < 2 {
+		return Task{}, actionNone
+	}
+	id, _, _ := strings.Cut(result[1], "\t")
+	action := actionView
+	switch result[0] {
+	case "ctrl-e":
+		action = actionEdit
+	case "ctrl-s":
+		action = actionStatus
+	case "ctrl-d":
+		action = actionDelete
+	}
+	return taskMap[id], action
+}
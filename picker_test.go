@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pickerTasks is a small workspace for the picker tests.
+func pickerTasks() []Task {
+	names := []string{"Fix login on Safari", "Update logging", "Write docs", "Login page redesign", "Clean up old lists"}
+	tasks := make([]Task, len(names))
+	for i, name := range names {
+		tasks[i] = Task{ID: string(rune('a' + i)), Name: name}
+	}
+	return tasks
+}
+
+// sendPicker passes msgs to the picker one by one and drops the commands.
+func sendPicker(p taskPicker, msgs ...tea.Msg) taskPicker {
+	for _, msg := range msgs {
+		updated, _ := p.Update(msg)
+		p = updated.(taskPicker)
+	}
+	return p
+}
+
+func TestPickerRanking(t *testing.T) {
+	p := newTaskPicker(nil)
+	p = sendPicker(p, TasksResponse{Tasks: pickerTasks()})
+	names := func() []string {
+		var names []string
+		for _, m := range p.matches {
+			names = append(names, m.Str)
+		}
+		return names
+	}
+	if got, want := names(), []string{"Fix login on Safari", "Update logging", "Write docs", "Login page redesign", "Clean up old lists"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("without a query = %q, want the tasks as loaded", got)
+	}
+
+	p.cursor = 4
+	p = sendPicker(p, typed("login"))
+	if got, want := names(), []string{"Login page redesign", "Fix login on Safari", "Update logging"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matches of login = %q, want %q", got, want)
+	}
+	if p.cursor != 2 {
+		t.Errorf("cursor = %d after the matches shrank to 3, want 2", p.cursor)
+	}
+	if task, _ := p.selected(); task.Name != "Update logging" {
+		t.Errorf("selected %q", task.Name)
+	}
+
+	p = sendPicker(p, typed("xyz"))
+	if _, ok := p.selected(); ok || len(p.matches) != 0 || p.cursor != 0 {
+		t.Errorf("no matches: selected %v, cursor %d", ok, p.cursor)
+	}
+	if _, cmd := p.Update(typed("enter")); cmd != nil {
+		t.Errorf("enter without a match quit the picker")
+	}
+}
+
+func TestPickerActions(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want taskAction
+	}{
+		{[]string{"enter"}, actionView},
+		{[]string{"tab", "j", "e"}, actionEdit},
+		{[]string{"esc", "s"}, actionStatus},
+		{[]string{"esc", "d"}, actionDelete},
+		{[]string{"esc", "q"}, actionNone},
+	}
+	for _, tt := range tests {
+		p := sendPicker(newTaskPicker(nil), TasksResponse{Tasks: pickerTasks()})
+		var cmd tea.Cmd
+		for _, k := range tt.keys {
+			var updated tea.Model
+			updated, cmd = p.Update(typed(k))
+			p = updated.(taskPicker)
+		}
+		if cmd == nil || p.action != tt.want {
+			t.Errorf("%q: action %d, want %d and quit", tt.keys, p.action, tt.want)
+		}
+		if tt.want == actionEdit && p.picked.Name != "Update logging" {
+			t.Errorf("%q picked %q", tt.keys, p.picked.Name)
+		}
+	}
+}
+
+func TestPickerPreview(t *testing.T) {
+	task := Task{ID: "86abc", Name: "Fix login", Content: "Steps to reproduce"}
+	task.Status.Status = "in progress"
+	task.Folder.Name, task.List.Name = "hidden", "Inbox"
+	task.Assignees = []Member{{Username: "ann"}, {Username: "bob"}}
+	task.Tags = []Tag{{Name: "bug"}}
+	preview := taskPreview(task, 40)
+	for _, want := range []string{"86abc", "in progress", "Inbox", "ann, bob", "bug", "Steps to reproduce"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("preview has no %q:\n%s", want, preview)
+		}
+	}
+	if strings.Contains(preview, "hidden") {
+		t.Errorf("preview shows the hidden folder:\n%s", preview)
+	}
+
+	p := sendPicker(newTaskPicker(nil), TasksResponse{Tasks: []Task{task}})
+	for _, tt := range []struct {
+		width   int
+		preview bool
+	}{{120, true}, {80, false}} {
+		p = sendPicker(p, tea.WindowSizeMsg{Width: tt.width, Height: 30})
+		if got := strings.Contains(p.View(), "Steps to reproduce"); got != tt.preview {
+			t.Errorf("width %d: preview shown = %v, want %v", tt.width, got, tt.preview)
+		}
+	}
+}
+
+// fakeFzf is an fzf that picks the second line with the key in FAKE_FZF_KEY,
+// or exits with FAKE_FZF_EXIT as when fzf is cancelled.
+const fakeFzf = `#!/bin/sh
+[ -n "$FAKE_FZF_EXIT" ] && exit "$FAKE_FZF_EXIT"
+printf '%s\n' "$FAKE_FZF_KEY"
+sed -n 2p
+`
+
+func TestPickWithFzf(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "fzf"), []byte(fakeFzf), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	fetch := func() tea.Msg { return TasksResponse{Tasks: pickerTasks()} }
+	tests := []struct {
+		key, exit string
+		want      taskAction
+		task      string
+	}{
+		{"", "", actionView, "b"},
+		{"ctrl-e", "", actionEdit, "b"},
+		{"ctrl-s", "", actionStatus, "b"},
+		{"ctrl-d", "", actionDelete, "b"},
+		{"", "130", actionNone, ""},
+	}
+	for _, tt := range tests {
+		t.Setenv("FAKE_FZF_KEY", tt.key)
+		t.Setenv("FAKE_FZF_EXIT", tt.exit)
+		task, action := pickWithFzf(fetch)
+		if action != tt.want || task.ID != tt.task {
+			t.Errorf("fzf with %q, exit %q: %q, %d, want %q, %d", tt.key, tt.exit, task.ID, action, tt.task, tt.want)
+		}
+	}
+}
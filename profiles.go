@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// --- PROFILES ---
+
+// profile holds the credentials of one ClickUp workspace.
+type profile struct {
+	Name     string `yaml:"-"`
+	APIToken string `yaml:"api_token,omitempty"`
+	TeamID   string `yaml:"team_id"`
+}
+
+func (p profile) FilterValue() string { return p.Name }
+func (p profile) Title() string       { return p.Name }
+func (p profile) Description() string { return "Team " + p.TeamID }
+
+// sortedProfiles returns the profiles ordered by name.
+func (c *config) sortedProfiles() []profile {
+	var profiles []profile
+	for _, p := range c.Profiles {
+		profiles = append(profiles, *p)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+// profileFlag is set by the global --profile flag.
+var profileFlag string
+
+// activeProfile returns the name of the profile to use: the --profile flag,
+// then CLUP_PROFILE, then the profile selected with `clup profile use`.
+func activeProfile(cfg *config) string {
+	if profileFlag != "" {
+		return profileFlag
+	}
+	if name := os.Getenv("CLUP_PROFILE"); name != "" {
+		return name
+	}
+	return cfg.CurrentProfile
+}
+
+// credentials returns the API token and team ID of the active profile, falling
+// back to CLICKUP_API_TOKEN and CLICKUP_TEAM_ID when no profile is configured.
+// The token is empty when none is stored.
+func credentials() (apiToken, teamID, profileName string, err error) {
+	loadConfig()
+	cfg, err := readConfig()
+	if err != nil {
+		return "", "", "", err
+	}
+	store, err := newCredentialStore(cfg.Credentials, true)
+	if err != nil {
+		return "", "", "", err
+	}
+	name := activeProfile(cfg)
+	if name != "" {
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			return "", "", "", fmt.Errorf("profile %q does not exist", name)
+		}
+		teamID = p.TeamID
+	} else {
+		teamID = os.Getenv("CLICKUP_TEAM_ID")
+	}
+	apiToken, err = lookupToken(cfg, store, name)
+	if errors.Is(err, errNoCredential) {
+		err = nil
+	}
+	return apiToken, teamID, name, err
+}
+
+// --- UPDATE & VIEW (PROFILE SELECTION) ---
+
+// startProfileSelection lists the configured profiles so the TUI can switch to
+// another workspace.
+func (m model) startProfileSelection() (model, tea.Cmd) {
+	cfg, err := readConfig()
+	if err != nil {
+		m.err = err
+		return m, tea.Quit
+	}
+	profiles := cfg.sortedProfiles()
+	if len(profiles) == 0 {
+		return m, nil
+	}
+	items := make([]list.Item, len(profiles))
+	for i, p := range profiles {
+		items[i] = p
+	}
+	h, v := appStyle.GetFrameSize()
+	m.profileList = newList(items, newItemDelegate(), m.width-h, m.height-v)
+	m.profileList.Title = "Switch Workspace"
+	for i, p := range profiles {
+		if p.Name == m.profile {
+			m.profileList.Select(i)
+		}
+	}
+	m.previousState = m.state
+	m.state = profileSelectionView
+	return m, nil
+}
+
+// switchProfile starts over at the space selection with the credentials of p.
+// When its token can't be read, the profile list stays open with the error.
+func (m model) switchProfile(p profile) (model, tea.Cmd) {
+	store, err := newCredentialStore(settings.Credentials, false)
+	var apiToken string
+	if err == nil {
+		apiToken, err = lookupToken(settings, store, p.Name)
+	}
+	if err != nil {
+		return m, m.profileList.NewStatusMessage(failureStyle.Render(fmt.Sprintf("Can't switch to %s: %v", p.Name, err)))
+	}
+	m.apiToken = apiToken
+	m.teamID = p.TeamID
+	m.profile = p.Name
+	m.spaceID = ""
+	m.listID = ""
+	m.selectedTask = Task{}
+	m = m.resetNewTask()
+	m.clearMarks()
+	m.state = spaceSelectionView
+	h, v := appStyle.GetFrameSize()
+	m.spaceList = newSpaceTree(spaceListTitle(m.profile), m.width-h, m.height-v)
+	m.tree = newHierarchy()
+	return m, fetchSpacesCmd(m.apiToken, m.teamID)
+}
+
+func spaceListTitle(profileName string) string {
+	if profileName == "" {
+		return "Select a Space"
+	}
+	return fmt.Sprintf("Select a Space (%s)", profileName)
+}
+
+func updateProfileSelection(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.profileList.SetSize(msg.Width-h, msg.Height-v)
+	case tea.KeyMsg:
+		if m.profileList.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "esc", "q":
+			m.state = m.previousState
+			return m, nil
+		case "enter":
+			selected, ok := m.profileList.SelectedItem().(profile)
+			if ok {
+				return m.switchProfile(selected)
+			}
+		}
+	}
+	m.profileList, cmd = m.profileList.Update(msg)
+	return m, cmd
+}
+
+// --- PROFILES (CLI) ---
+
+var (
+	profileToken string
+	profileTeam  string
+	profileUse   bool
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage workspace profiles",
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or replace a profile",
+	Long: `Add or replace a profile. The token is kept in the credential store set
+with credentials.store in the config file, like with clup auth login.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := readConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		store, err := newCredentialStore(cfg.Credentials, true)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		in := bufio.NewReader(os.Stdin)
+		if profileToken == "" {
+			profileToken = promptSecret(in, "ClickUp API Token: ")
+		}
+		if profileTeam == "" {
+			profileTeam = prompt(in, "Team ID: ")
+		}
+		if profileToken == "" || profileTeam == "" {
+			fmt.Println("Error adding profile: API token and team ID are required.")
+			os.Exit(1)
+		}
+		if profileUse {
+			cfg.CurrentProfile = args[0]
+		}
+		if err := saveLogin(cfg, store, args[0], profileToken, profileTeam); err != nil {
+			fmt.Println("Error saving profile:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved profile %s with its token in %s.\n", args[0], store.Name())
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := readConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		profiles := cfg.sortedProfiles()
+		if len(profiles) == 0 {
+			fmt.Println("No profiles. Add one with `clup profile add <name>`.")
+			return
+		}
+		active := activeProfile(cfg)
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, p := range profiles {
+			marker := " "
+			if p.Name == active {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "%s %s\t%s\n", marker, p.Name, p.TeamID)
+		}
+		w.Flush()
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a profile the default",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := readConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		if _, ok := cfg.Profiles[args[0]]; !ok {
+			fmt.Printf("Error: profile %q does not exist.\n", args[0])
+			os.Exit(1)
+		}
+		cfg.CurrentProfile = args[0]
+		if err := cfg.save(); err != nil {
+			fmt.Println("Error saving config:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Using profile %s.\n", args[0])
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a profile",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := readConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			os.Exit(1)
+		}
+		if _, ok := cfg.Profiles[args[0]]; !ok {
+			fmt.Printf("Error: profile %q does not exist.\n", args[0])
+			os.Exit(1)
+		}
+		store, err := newCredentialStore(cfg.Credentials, true)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		if err := store.Delete(args[0]); err != nil && !errors.Is(err, errNoCredential) {
+			fmt.Println("Error removing token:", err)
+			os.Exit(1)
+		}
+		delete(cfg.Profiles, args[0])
+		if cfg.CurrentProfile == args[0] {
+			cfg.CurrentProfile = ""
+		}
+		if err := cfg.save(); err != nil {
+			fmt.Println("Error saving config:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed profile %s.\n", args[0])
+	},
+}
+
+// prompt reads one line from in after printing label.
+func prompt(in *bufio.Reader, label string) string {
+	fmt.Print(label)
+	line, _ := in.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func init() {
+	profileAddCmd.Flags().StringVar(&profileToken, "token", "", "ClickUp API token")
+	profileAddCmd.Flags().StringVar(&profileTeam, "team", "", "ClickUp team (workspace) ID")
+	profileAddCmd.Flags().BoolVar(&profileUse, "use", false, "make the profile the default")
+	profileCmd.AddCommand(profileAddCmd, profileListCmd, profileUseCmd, profileRemoveCmd)
+}
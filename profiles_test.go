@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestActiveProfile(t *testing.T) {
+	defer func(flag string) { profileFlag = flag }(profileFlag)
+	cfg := &config{CurrentProfile: "work"}
+	tests := []struct {
+		flag, env, want string
+	}{
+		{"", "", "work"},
+		{"", "home", "home"},
+		{"ci", "home", "ci"},
+		{"ci", "", "ci"},
+	}
+	for _, tt := range tests {
+		profileFlag = tt.flag
+		t.Setenv("CLUP_PROFILE", tt.env)
+		if got := activeProfile(cfg); got != tt.want {
+			t.Errorf("activeProfile with --profile %q and CLUP_PROFILE %q = %q, want %q", tt.flag, tt.env, got, tt.want)
+		}
+	}
+}
+
+// useCommandStore sets up an empty config whose tokens are kept by the
+// credentialHelper script, and returns that store.
+func useCommandStore(t *testing.T) credentialStore {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("CLUP_PROFILE", "")
+	helper := filepath.Join(dir, "helper")
+	if err := os.WriteFile(helper, []byte(credentialHelper), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{Credentials: credentialSettings{Store: "command", Command: helper}}
+	if err := cfg.save(); err != nil {
+		t.Fatal(err)
+	}
+	return commandStore{command: helper}
+}
+
+func TestProfileCommands(t *testing.T) {
+	store := useCommandStore(t)
+	defer func(flag string) { profileFlag = flag }(profileFlag)
+	defer func(token, team string, use bool) {
+		profileToken, profileTeam, profileUse = token, team, use
+	}(profileToken, profileTeam, profileUse)
+
+	profileToken, profileTeam, profileUse = "pk_work", "1", false
+	profileAddCmd.Run(profileAddCmd, []string{"work"})
+	profileToken, profileTeam, profileUse = "pk_home", "2", true
+	profileAddCmd.Run(profileAddCmd, []string{"home"})
+
+	data, err := os.ReadFile(configPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "pk_") {
+		t.Errorf("config file has a plaintext token:\n%s", data)
+	}
+	if token, err := store.Get("work"); err != nil || token != "pk_work" {
+		t.Errorf("stored token of work = %q, %v", token, err)
+	}
+
+	check := func(label, wantToken, wantTeam, wantProfile string) {
+		t.Helper()
+		token, team, name, err := credentials()
+		if err != nil || token != wantToken || team != wantTeam || name != wantProfile {
+			t.Errorf("%s: credentials = %q, %q, %q, %v, want %q, %q, %q", label, token, team, name, err, wantToken, wantTeam, wantProfile)
+		}
+	}
+	check("profile add --use", "pk_home", "2", "home")
+	t.Setenv("CLUP_PROFILE", "work")
+	check("CLUP_PROFILE", "pk_work", "1", "work")
+	profileFlag = "home"
+	check("--profile over CLUP_PROFILE", "pk_home", "2", "home")
+	profileFlag = ""
+	t.Setenv("CLUP_PROFILE", "")
+
+	profileUseCmd.Run(profileUseCmd, []string{"work"})
+	check("profile use", "pk_work", "1", "work")
+
+	profileRemoveCmd.Run(profileRemoveCmd, []string{"work"})
+	cfg, err := readConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Profiles["work"]; ok || cfg.CurrentProfile != "" {
+		t.Errorf("after profile remove: profiles %v, current %q", cfg.Profiles, cfg.CurrentProfile)
+	}
+	if _, err := store.Get("work"); !errors.Is(err, errNoCredential) {
+		t.Errorf("token of a removed profile: %v, want errNoCredential", err)
+	}
+	if token, err := store.Get("home"); err != nil || token != "pk_home" {
+		t.Errorf("token of home after removing work = %q, %v", token, err)
+	}
+}
+
+func TestSwitchProfileReadsStoredToken(t *testing.T) {
+	t.Setenv("CLUP_PASSPHRASE", "secret")
+	defer func(s *config) { settings = s }(settings)
+	settings = &config{
+		Credentials: credentialSettings{Store: "file", File: filepath.Join(t.TempDir(), "credentials.enc")},
+		Profiles: map[string]*profile{
+			"work": {Name: "work", TeamID: "1"},
+			"home": {Name: "home", TeamID: "2"},
+		},
+	}
+	store, err := newCredentialStore(settings.Credentials, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("work", "pk_work"); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newModel("pk_old", "9", "old", false)
+	m.state = profileSelectionView
+	m.profileList = newList(nil, newItemDelegate(), 80, 40)
+
+	switched, _ := m.switchProfile(*settings.Profiles["work"])
+	if switched.apiToken != "pk_work" || switched.teamID != "1" || switched.state != spaceSelectionView {
+		t.Errorf("switch to work: token %q, team %q, state %d", switched.apiToken, switched.teamID, switched.state)
+	}
+
+	failed, _ := m.switchProfile(*settings.Profiles["home"])
+	if failed.apiToken != "pk_old" || failed.profile != "old" || failed.state != profileSelectionView {
+		t.Errorf("switch to home without a token: token %q, profile %q, state %d", failed.apiToken, failed.profile, failed.state)
+	}
+	if view := failed.profileList.View(); !strings.Contains(view, "no token stored") {
+		t.Errorf("profile list does not show the error:\n%s", view)
+	}
+}
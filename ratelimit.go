@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// --- RATE LIMITING ---
+
+// httpClient is shared by every API call so that all of them honour the
+// per-token rate limit reported by ClickUp.
+var httpClient = &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}}
+
+const (
+	rateLimitRetries = 3
+	rateLimitMaxWait = time.Minute
+)
+
+// rateLimitTransport delays requests once ClickUp reports that the rate limit
+// is used up, and retries requests that were rejected with 429 Too Many
+// Requests after the limit resets.
+type rateLimitTransport struct {
+	base         http.RoundTripper
+	mu           sync.Mutex
+	blockedUntil time.Time
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		t.wait()
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.observe(resp, attempt)
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= rateLimitRetries {
+			return resp, nil
+		}
+		// A request whose body can't be sent again gets the 429 as it is,
+		// so that the caller can still read it.
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		resp.Body.Close()
+	}
+}
+
+func (t *rateLimitTransport) wait() {
+	t.mu.Lock()
+	delay := time.Until(t.blockedUntil)
+	t.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(min(delay, rateLimitMaxWait))
+	}
+}
+
+// observe records when requests may be sent again, based on the
+// X-RateLimit-* headers of resp.
+func (t *rateLimitTransport) observe(resp *http.Response, attempt int) {
+	limited := resp.StatusCode == http.StatusTooManyRequests
+	if !limited && resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	until := time.Now().Add(time.Second << attempt)
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		until = time.Unix(reset, 0)
+	}
+	t.mu.Lock()
+	if until.After(t.blockedUntil) {
+		t.blockedUntil = until
+	}
+	t.mu.Unlock()
+}
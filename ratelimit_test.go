@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// limitedServer answers with 429 Too Many Requests until it has been called
+// limited times.
+func limitedServer(t *testing.T, limited int) (*httptest.Server, *int) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+		if calls <= limited {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, "rate limited")
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+// serverTransport sends every request to a test server.
+type serverTransport struct{ url *neturl.URL }
+
+func (s serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = s.url.Scheme, s.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// useServer sends the API requests of a test to handler instead of ClickUp.
+func useServer(t *testing.T, handler http.Handler) {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	u, _ := neturl.Parse(srv.URL)
+	client := httpClient
+	t.Cleanup(func() { httpClient = client })
+	httpClient = &http.Client{Transport: serverTransport{u}}
+}
+
+func TestRateLimitRetriesReplayableRequests(t *testing.T) {
+	srv, calls := limitedServer(t, 1)
+	client := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}}
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "payload" || *calls != 2 {
+		t.Errorf("got %d %q after %d calls, want 200 %q after 2", resp.StatusCode, body, *calls, "payload")
+	}
+}
+
+func TestRateLimitReturnsUnreplayableResponse(t *testing.T) {
+	srv, calls := limitedServer(t, 1)
+	client := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}}
+	req, _ := http.NewRequest("POST", srv.URL, io.NopCloser(strings.NewReader("payload")))
+	req.GetBody = nil
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading the 429 response: %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests || string(body) != "rate limited" || *calls != 1 {
+		t.Errorf("got %d %q after %d calls, want 429 %q after 1", resp.StatusCode, body, *calls, "rate limited")
+	}
+}
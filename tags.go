@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// --- TAGS ---
+
+// Tags belong to a space in ClickUp and have a text and a background color.
+// Sets of tags are kept as maps from the lowercase tag name, the way ClickUp
+// compares them.
+
+func (t Tag) FilterValue() string { return t.Name }
+
+type TagsResponse struct {
+	Tags []Tag `json:"tags"`
+}
+
+func tagKey(name string) string { return strings.ToLower(name) }
+
+// tagSet returns tags as a set.
+func tagSet(tags []Tag) map[string]Tag {
+	set := make(map[string]Tag, len(tags))
+	for _, t := range tags {
+		set[tagKey(t.Name)] = t
+	}
+	return set
+}
+
+// sortedTags returns the tags of a set ordered by name.
+func sortedTags(set map[string]Tag) []Tag {
+	tags := make([]Tag, 0, len(set))
+	for _, t := range set {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tagKey(tags[i].Name) < tagKey(tags[j].Name) })
+	return tags
+}
+
+// tagTerm is a query term that matches tasks with any of the tags.
+func tagTerm(set map[string]Tag) string {
+	var names []string
+	for _, t := range sortedTags(set) {
+		names = append(names, t.Name)
+	}
+	value := strings.Join(names, ",")
+	if strings.ContainsAny(value, " \t") {
+		value = "'" + value + "'"
+	}
+	return "tag=" + value
+}
+
+// hasAnyTag reports whether a task has one of the tags in set.
+func hasAnyTag(task Task, set map[string]Tag) bool {
+	for _, t := range task.Tags {
+		if _, ok := set[tagKey(t.Name)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func fetchSpaceTagsCmd(apiToken, spaceID string) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/space/%s/tag", spaceID)
+		body, err := sendContainerRequest(apiToken, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("fetch tags failed: %v", err)
+		}
+		var tags TagsResponse
+		if err := json.Unmarshal(body, &tags); err != nil {
+			return err
+		}
+		return tags
+	}
+}
+
+// createSpaceTag adds a tag to a space. ClickUp picks the colors when none
+// are given.
+func createSpaceTag(apiToken, spaceID string, tag Tag) error {
+	url := fmt.Sprintf("https://api.clickup.com/api/v2/space/%s/tag", spaceID)
+	payload := map[string]map[string]string{"tag": {"name": tag.Name}}
+	if tag.Bg != "" {
+		payload["tag"]["tag_bg"] = tag.Bg
+		payload["tag"]["tag_fg"] = tag.Fg
+	}
+	if _, err := sendContainerRequest(apiToken, "POST", url, payload); err != nil {
+		return fmt.Errorf("create tag failed: %v", err)
+	}
+	return nil
+}
+
+// tagDelegate renders tags in their colors with a checkbox, for picking
+// several of them.
+type tagDelegate struct {
+	selected map[string]Tag
+}
+
+func (d tagDelegate) Height() int                               { return 1 }
+func (d tagDelegate) Spacing() int                              { return 0 }
+func (d tagDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+func (d tagDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	tag, ok := listItem.(Tag)
+	if !ok {
+		return
+	}
+	isSelected := "[ ]"
+	if _, exists := d.selected[tagKey(tag.Name)]; exists {
+		isSelected = "[x]"
+	}
+	line := isSelected + " " + tagBadges([]Tag{tag})
+	if index == m.Index() {
+		fmt.Fprint(w, focusedStyle.Render("> ")+line)
+	} else {
+		fmt.Fprint(w, "  "+line)
+	}
+}
+
+// --- UPDATE & VIEW (TAG PICKER) ---
+
+// The tag picker edits a copy of the selection, which enter keeps and esc
+// throws away. It picks the tags of a task in the edit view, and the tags to
+// filter the task list by.
+
+func (m model) startTagPicker(title string, selected map[string]Tag, tags []Tag) model {
+	m.previousState = m.state
+	m.state = tagPickerView
+	m.tagChoice = make(map[string]Tag, len(selected))
+	for k, t := range selected {
+		m.tagChoice[k] = t
+	}
+	items := make([]list.Item, len(tags))
+	for i, t := range tags {
+		items[i] = t
+	}
+	h, v := appStyle.GetFrameSize()
+	m.tagList = newList(items, tagDelegate{selected: m.tagChoice}, m.width-h, m.height-v)
+	m.tagList.Title = title
+	m.tagList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "done")),
+		}
+	}
+	return m
+}
+
+func updateTagPicker(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.tagList.SetSize(msg.Width-h, msg.Height-v)
+	case TagsResponse:
+		// Tags of the space, along with tags of the task that are no longer
+		// in it.
+		known := tagSet(msg.Tags)
+		for k, t := range m.tagChoice {
+			if _, ok := known[k]; !ok {
+				known[k] = t
+			}
+		}
+		tags := sortedTags(known)
+		items := make([]list.Item, len(tags))
+		for i, t := range tags {
+			items[i] = t
+		}
+		return m, m.tagList.SetItems(items)
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if m.tagList.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case " ":
+			if tag, ok := m.tagList.SelectedItem().(Tag); ok {
+				if _, exists := m.tagChoice[tagKey(tag.Name)]; exists {
+					delete(m.tagChoice, tagKey(tag.Name))
+				} else {
+					m.tagChoice[tagKey(tag.Name)] = tag
+				}
+			}
+			return m, nil
+		case "esc":
+			if m.tagList.FilterState() == list.FilterApplied {
+				break
+			}
+			m.state = m.previousState
+			return m, nil
+		case "enter":
+			m.state = m.previousState
+			if m.state == editTaskView {
+				m.editTags = m.tagChoice
+				return m, nil
+			}
+			m.tagFilter = m.tagChoice
+			m.showTasks()
+			m.list.Title = m.taskListTitle()
+			return m, m.fetchSelectedCounts()
+		}
+	}
+	m.tagList, cmd = m.tagList.Update(msg)
+	return m, cmd
+}
+
+// startTagFilter offers the tags of the loaded tasks to filter the task list
+// by.
+func (m model) startTagFilter() model {
+	available := make(map[string]Tag)
+	for _, task := range m.tasks {
+		for _, t := range task.Tags {
+			available[tagKey(t.Name)] = t
+		}
+	}
+	return m.startTagPicker("Show tasks with any of these tags", m.tagFilter, sortedTags(available))
+}
+
+// editTagCmds adds and removes tags so that the task has the tags picked in
+// the edit view.
+func (m model) editTagCmds() []tea.Cmd {
+	var cmds []tea.Cmd
+	current := tagSet(m.selectedTask.Tags)
+	for k, t := range m.editTags {
+		if _, ok := current[k]; !ok {
+			cmds = append(cmds, addTagCmd(m.apiToken, m.selectedTask.ID, t.Name))
+		}
+	}
+	for k, t := range current {
+		if _, ok := m.editTags[k]; !ok {
+			cmds = append(cmds, removeTagCmd(m.apiToken, m.selectedTask.ID, t.Name))
+		}
+	}
+	return cmds
+}
+
+// --- TAGS (CLI) ---
+
+var (
+	tagSpace string
+	tagColor string
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "List tags and tag tasks",
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the tags of a space",
+	Long: `List the tags of a space with their colors. Without --space, the default
+space from the config file is used, or every space when there is none.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SPACE\tNAME\tCOLOR")
+		for _, s := range spacesFor(apiToken, teamID, tagSpace) {
+			for _, t := range mustFetchSpaceTags(apiToken, s.ID) {
+				swatch := lipgloss.NewStyle().Foreground(lipgloss.Color(t.Bg)).Render("■")
+				fmt.Fprintf(w, "%s\t%s\t%s %s\n", s.Name, t.Name, swatch, t.Bg)
+			}
+		}
+		w.Flush()
+	},
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <task> <tag>...",
+	Short: "Add tags to a task",
+	Long: `Add tags to a task. Tags that don't exist in the task's space yet are
+created there first, in the color given with --color or one ClickUp picks.`,
+	Example: `  clup tag add 86abc123 bug triage
+  clup tag add ENG-1234 customer --color '#e50000'`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if tagColor != "" && !tagColorPattern.MatchString(tagColor) {
+			fmt.Printf("Error: --color %q is not a color like #e50000.\n", tagColor)
+			os.Exit(1)
+		}
+		apiToken, teamID := requireCredentials()
+		task := mustFetchTaskRef(apiToken, teamID, args[0])
+		existing := tagSet(mustFetchSpaceTags(apiToken, task.Space.ID))
+		var names []string
+		for _, name := range args[1:] {
+			if tag, ok := existing[tagKey(name)]; ok {
+				name = tag.Name
+			} else {
+				tag := Tag{Name: name}
+				if tagColor != "" {
+					tag.Bg, tag.Fg = tagColor, contrastText(tagColor)
+				}
+				if err := createSpaceTag(apiToken, task.Space.ID, tag); err != nil {
+					fmt.Println("Error:", err)
+					os.Exit(1)
+				}
+				fmt.Printf("Created tag %s.\n", name)
+				existing[tagKey(name)] = tag
+			}
+			if err, ok := addTagCmd(apiToken, task.ID, name)().(error); ok {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			names = append(names, name)
+		}
+		fmt.Printf("Tagged %s with %s.\n", task.Name, strings.Join(names, ", "))
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:     "remove <task> <tag>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove tags from a task",
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		task := mustFetchTaskRef(apiToken, teamID, args[0])
+		current := tagSet(task.Tags)
+		for _, name := range args[1:] {
+			tag, ok := current[tagKey(name)]
+			if !ok {
+				fmt.Printf("%s is not tagged with %s.\n", task.Name, name)
+				continue
+			}
+			if err, ok := removeTagCmd(apiToken, task.ID, tag.Name)().(error); ok {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Removed %s from %s.\n", tag.Name, task.Name)
+		}
+	},
+}
+
+func mustFetchSpaceTags(apiToken, spaceID string) []Tag {
+	switch msg := fetchSpaceTagsCmd(apiToken, spaceID)().(type) {
+	case error:
+		fmt.Println("Error:", msg)
+		os.Exit(1)
+	case TagsResponse:
+		return msg.Tags
+	}
+	return nil
+}
+
+func init() {
+	tagListCmd.Flags().StringVar(&tagSpace, "space", "", "space name or ID (defaults to default_space)")
+	tagAddCmd.Flags().StringVar(&tagColor, "color", "", "color of new tags, as #rrggbb")
+	tagCmd.AddCommand(tagListCmd, tagAddCmd, tagRemoveCmd)
+}
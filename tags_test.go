@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// useTags serves the task 86abc, tagged Bug and UI, in a space with the tags
+// bug, ui and backend. It records the other requests, with their bodies.
+func useTags(t *testing.T) *[]string {
+	var requests []string
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v2/task/86abc":
+			w.Write([]byte(`{"id":"86abc","name":"Fix login","space":{"id":"100"},"tags":[{"name":"Bug"},{"name":"UI"}]}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v2/space/100/tag":
+			w.Write([]byte(`{"tags":[{"name":"bug"},{"name":"ui"},{"name":"backend"}]}`))
+		default:
+			request := r.Method + " " + r.URL.Path
+			var body map[string]interface{}
+			if json.NewDecoder(r.Body).Decode(&body) == nil {
+				data, _ := json.Marshal(body)
+				request += " " + string(data)
+			}
+			requests = append(requests, request)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	useProfile(t)
+	return &requests
+}
+
+func TestTagSetIgnoresCase(t *testing.T) {
+	set := tagSet([]Tag{{Name: "Bug"}, {Name: "needs Review"}})
+	for _, name := range []string{"bug", "BUG", "Needs review"} {
+		if _, ok := set[tagKey(name)]; !ok {
+			t.Errorf("%q not in %v", name, set)
+		}
+	}
+	task := Task{Tags: []Tag{{Name: "BUG"}}}
+	if !hasAnyTag(task, set) {
+		t.Errorf("task tagged BUG has none of %v", set)
+	}
+}
+
+func TestEditTagCmds(t *testing.T) {
+	requests := useTags(t)
+	m := newModel("pk_1", "1", "", false)
+	m.selectedTask = Task{ID: "86abc", Tags: []Tag{{Name: "Bug"}, {Name: "UI"}}}
+	// Picked in the tag picker, where the space spells bug in lowercase.
+	m.editTags = tagSet([]Tag{{Name: "bug"}, {Name: "backend"}})
+	for _, cmd := range m.editTagCmds() {
+		if err, ok := cmd().(error); ok {
+			t.Fatal(err)
+		}
+	}
+	sort.Strings(*requests)
+	want := []string{"DELETE /api/v2/task/86abc/tag/UI", "POST /api/v2/task/86abc/tag/backend"}
+	if !reflect.DeepEqual(*requests, want) {
+		t.Errorf("requests %q, want %q", *requests, want)
+	}
+
+	*requests = nil
+	m.editTags = tagSet([]Tag{{Name: "BUG"}, {Name: "ui"}})
+	if cmds := m.editTagCmds(); len(cmds) != 0 {
+		t.Errorf("%d changes for the same tags in other cases", len(cmds))
+	}
+}
+
+func TestTagCommands(t *testing.T) {
+	defer func(color string) { tagColor = color }(tagColor)
+	tests := []struct {
+		name   string
+		cmd    string
+		args   []string
+		color  string
+		want   []string
+		output []string
+	}{
+		{"add existing tags in any case", "add", []string{"86abc", "BACKEND", "Bug"}, "",
+			[]string{"POST /api/v2/task/86abc/tag/backend", "POST /api/v2/task/86abc/tag/bug"},
+			[]string{"Tagged Fix login with backend, bug."}},
+		{"add a missing tag", "add", []string{"86abc", "triage", "Triage"}, "#e50000",
+			[]string{
+				`POST /api/v2/space/100/tag {"tag":{"name":"triage","tag_bg":"#e50000","tag_fg":"#FFFFFF"}}`,
+				"POST /api/v2/task/86abc/tag/triage",
+				"POST /api/v2/task/86abc/tag/triage",
+			},
+			[]string{"Created tag triage."}},
+		{"add a missing tag without a color", "add", []string{"86abc", "needs review"}, "",
+			[]string{`POST /api/v2/space/100/tag {"tag":{"name":"needs review"}}`, "POST /api/v2/task/86abc/tag/needs review"},
+			[]string{"Created tag needs review."}},
+		{"remove in any case", "remove", []string{"86abc", "bug", "ui", "backend"}, "",
+			[]string{"DELETE /api/v2/task/86abc/tag/Bug", "DELETE /api/v2/task/86abc/tag/UI"},
+			[]string{"Removed Bug from Fix login.", "Removed UI from Fix login.", "Fix login is not tagged with backend."}},
+	}
+	for _, tt := range tests {
+		requests := useTags(t)
+		tagColor = tt.color
+		cmd := tagAddCmd
+		if tt.cmd == "remove" {
+			cmd = tagRemoveCmd
+		}
+		out := runCommand(t, cmd, "", tt.args...)
+		if !reflect.DeepEqual(*requests, tt.want) {
+			t.Errorf("%s: requests %q, want %q", tt.name, *requests, tt.want)
+		}
+		for _, want := range tt.output {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: printed %q, want %q", tt.name, out, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- TASK LIST DELEGATE ---
+
+// Every task takes two lines in the task list. The first has the status,
+// the name, the priority and the due date; the second the list, assignees,
+// tags and the number of subtasks and comments. Less important parts are
+// left out as the terminal gets narrower.
+
+// taskDelegate renders tasks in the task list, with a marker in front of
+// every task that is part of the current multi-selection.
+type taskDelegate struct {
+	marked       map[string]struct{}
+	visualMode   bool
+	visualAnchor int
+	stats        taskStats
+	// loc is the time zone due dates are shown in.
+	loc *time.Location
+}
+
+func newTaskDelegate(marked map[string]struct{}, visualMode bool, visualAnchor int, stats taskStats, loc *time.Location) taskDelegate {
+	return taskDelegate{
+		marked:       marked,
+		visualMode:   visualMode,
+		visualAnchor: visualAnchor,
+		stats:        stats,
+		loc:          loc,
+	}
+}
+
+func (d taskDelegate) Height() int                               { return 2 }
+func (d taskDelegate) Spacing() int                              { return 1 }
+func (d taskDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+
+func (d taskDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	if header, ok := listItem.(taskGroupHeader); ok {
+		header.render(w, m.Width())
+		return
+	}
+	task, ok := listItem.(Task)
+	if !ok {
+		return
+	}
+	width := m.Width() - 4
+	selected := index == m.Index() && m.FilterState() != list.Filtering
+
+	marker := "  "
+	if d.isMarked(m, index, listItem) {
+		marker = focusedStyle.Render("● ")
+	}
+	border := "  "
+	name := task.Name
+	if selected {
+		border = focusedStyle.Render("│ ")
+		name = focusedStyle.Render(name)
+	}
+
+	// First line: status, name, priority and due date.
+	status := statusPill(task, width)
+	right := strings.TrimSpace(priorityFlag(task, width) + " " + dueBadge(task, time.Now().In(d.loc)))
+	nameWidth := width - lipgloss.Width(status) - lipgloss.Width(right) - 2
+	first := status + " " + ansi.Truncate(name, max(nameWidth, 10), "…")
+	if right != "" {
+		gap := width - lipgloss.Width(first) - lipgloss.Width(right)
+		first += strings.Repeat(" ", max(gap, 1)) + right
+	}
+
+	// Second line: where the task is and who works on it.
+	var details []string
+	if location := taskLocation(task); location != "" && width >= 70 {
+		details = append(details, blurredStyle.Render(location))
+	}
+	if initials := assigneeInitials(task); initials != "" {
+		details = append(details, initials)
+	}
+	if width >= 90 && len(task.Tags) > 0 {
+		details = append(details, tagBadges(task.Tags))
+	}
+	if counts := d.stats.render(task); counts != "" && width >= 50 {
+		details = append(details, counts)
+	}
+	second := strings.Join(details, blurredStyle.Render(" · "))
+
+	fmt.Fprint(w, marker+border+ansi.Truncate(first, width, "…")+"\n")
+	fmt.Fprint(w, "  "+border+ansi.Truncate(second, width, "…"))
+}
+
+// statusPill shows the status in its ClickUp color, or just a colored dot
+// when space is short.
+func statusPill(task Task, width int) string {
+	color := task.Status.Color
+	if color == "" {
+		color = theme.Muted
+	}
+	if width < 60 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("●")
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(contrastText(color))).
+		Background(lipgloss.Color(color)).
+		Padding(0, 1).
+		Render(strings.ToUpper(task.Status.Status))
+}
+
+// contrastText picks black or white text for a background color given as
+// #rrggbb. Other colors get white text.
+func contrastText(background string) string {
+	hex := strings.TrimPrefix(background, "#")
+	if len(hex) != 6 {
+		return "#FFFFFF"
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return "#FFFFFF"
+	}
+	r, g, b := float64(rgb>>16&0xff), float64(rgb>>8&0xff), float64(rgb&0xff)
+	if 0.299*r+0.587*g+0.114*b > 150 {
+		return "#000000"
+	}
+	return "#FFFFFF"
+}
+
+// priorityFlag is a flag in the priority's color, with its name when there is
+// room for it.
+func priorityFlag(task Task, width int) string {
+	p := taskPriorityValue(task)
+	if p == 0 {
+		return ""
+	}
+	color := task.Priority.Color
+	if color == "" {
+		color = theme.Muted
+	}
+	flag := "⚑"
+	if width >= 80 {
+		flag += " " + priorityName(p)
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(flag)
+}
+
+// dueBadge shows the due date relative to now, in the error color when the
+// task is overdue and highlighted when it is due today.
+func dueBadge(task Task, now time.Time) string {
+	due, ok := msTime(task.DueDate)
+	if !ok {
+		return ""
+	}
+	label, days := relativeDate(due, now)
+	switch {
+	case days < 0:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Error)).Render(label)
+	case days == 0:
+		return focusedStyle.Bold(true).Render(label)
+	}
+	return blurredStyle.Render(label)
+}
+
+// relativeDate describes t relative to now in days, along with the number of
+// calendar days between them.
+func relativeDate(t, now time.Time) (string, int) {
+	t = t.In(now.Location())
+	// The dates are compared in UTC, where every day has 24 hours.
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = t.Date()
+	days := int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Sub(today).Hours() / 24)
+	switch {
+	case days == -1:
+		return "yesterday", days
+	case days < 0:
+		return fmt.Sprintf("%dd overdue", -days), days
+	case days == 0:
+		return "today", days
+	case days == 1:
+		return "tomorrow", days
+	case days < 7:
+		return t.Format("Mon"), days
+	case t.Year() == now.Year():
+		return t.Format("Jan 2"), days
+	}
+	return t.Format("Jan 2, 2006"), days
+}
+
+// taskLocation is the folder and list of a task.
+func taskLocation(task Task) string {
+	if folder := taskFolder(task); folder != "" {
+		return folder + " / " + task.List.Name
+	}
+	return task.List.Name
+}
+
+// taskFolder is the folder of a task, or empty for lists outside of folders,
+// which ClickUp puts in a folder named "hidden".
+func taskFolder(task Task) string {
+	if task.Folder.Name == "hidden" {
+		return ""
+	}
+	return task.Folder.Name
+}
+
+// assigneeInitials shows the initials of every assignee in their ClickUp
+// color.
+func assigneeInitials(task Task) string {
+	var out []string
+	for _, a := range task.Assignees {
+		initials := a.Initials
+		if initials == "" {
+			initials = initialsOf(a.Username)
+		}
+		style := lipgloss.NewStyle().Bold(true)
+		if a.Color != "" {
+			style = style.Foreground(lipgloss.Color(a.Color))
+		}
+		out = append(out, style.Render(initials))
+	}
+	return strings.Join(out, " ")
+}
+
+// initialsOf returns the first letters of the first two words of name.
+func initialsOf(name string) string {
+	var initials []rune
+	for _, word := range strings.Fields(strings.NewReplacer(".", " ", "_", " ", "-", " ").Replace(name)) {
+		initials = append(initials, []rune(strings.ToUpper(word))[0])
+		if len(initials) == 2 {
+			break
+		}
+	}
+	return string(initials)
+}
+
+// tagBadges shows tags in their ClickUp colors.
+func tagBadges(tags []Tag) string {
+	var out []string
+	for _, t := range tags {
+		style := lipgloss.NewStyle()
+		if t.Bg != "" {
+			style = style.Foreground(lipgloss.Color(t.Bg))
+		}
+		out = append(out, style.Render("#"+t.Name))
+	}
+	return strings.Join(out, " ")
+}
+
+// --- SUBTASK AND COMMENT COUNTS ---
+
+// The team task endpoint sends neither subtasks nor comments, so they are
+// counted for the selected task when the cursor reaches it. The counts are
+// kept until the task changes.
+
+// taskStats holds the counts loaded so far. It is shared between copies of
+// the model.
+type taskStats struct {
+	counts map[string]taskCounts
+	// requested maps the tasks whose counts were requested to their
+	// date_updated at the time.
+	requested map[string]string
+}
+
+type taskCounts struct {
+	subtasks int
+	comments int
+	// more is set when there may be more comments than were loaded.
+	more bool
+	// updated is the date_updated of the task that was counted.
+	updated string
+}
+
+func newTaskStats() taskStats {
+	return taskStats{counts: make(map[string]taskCounts), requested: make(map[string]string)}
+}
+
+// forget drops all counts, after a change that may not show in the
+// date_updated of a task, such as a new comment.
+func (s taskStats) forget() {
+	clear(s.counts)
+	clear(s.requested)
+}
+
+type taskCountsMsg struct {
+	taskID string
+	counts taskCounts
+}
+
+func (s taskStats) render(task Task) string {
+	c, ok := s.counts[task.ID]
+	if !ok || c.updated != task.DateUpdated {
+		return ""
+	}
+	var parts []string
+	if c.subtasks > 0 {
+		parts = append(parts, fmt.Sprintf("↳%d", c.subtasks))
+	}
+	if c.comments > 0 {
+		comments := fmt.Sprintf("✉%d", c.comments)
+		if c.more {
+			comments += "+"
+		}
+		parts = append(parts, comments)
+	}
+	return blurredStyle.Render(strings.Join(parts, " "))
+}
+
+// commentPageSize is how many comments ClickUp returns at once.
+const commentPageSize = 25
+
+func fetchTaskCountsCmd(apiToken, teamID string, task Task) tea.Cmd {
+	return func() tea.Msg {
+		counts := taskCounts{updated: task.DateUpdated}
+		if msg, ok := fetchTaskRefCmd(apiToken, teamID, task.ID)().(Task); ok {
+			counts.subtasks = len(msg.Subtasks)
+		}
+		if msg, ok := fetchCommentsCmd(apiToken, task.ID)().(CommentsResponse); ok {
+			counts.comments = len(msg.Comments)
+			counts.more = counts.comments == commentPageSize
+		}
+		return taskCountsMsg{taskID: task.ID, counts: counts}
+	}
+}
+
+// fetchSelectedCounts loads the counts of the selected task, unless they
+// were requested since it last changed.
+func (m model) fetchSelectedCounts() tea.Cmd {
+	task, ok := m.list.SelectedItem().(Task)
+	if !ok {
+		return nil
+	}
+	if updated, ok := m.stats.requested[task.ID]; ok && updated == task.DateUpdated {
+		return nil
+	}
+	m.stats.requested[task.ID] = task.DateUpdated
+	return fetchTaskCountsCmd(m.apiToken, m.teamID, task)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRelativeDate(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	at := func(loc *time.Location, y int, m time.Month, d, h int) time.Time {
+		return time.Date(y, m, d, h, 0, 0, 0, loc)
+	}
+	now := at(ny, 2026, time.March, 6, 18) // a Friday
+	tests := []struct {
+		due   time.Time
+		label string
+		days  int
+	}{
+		{at(ny, 2026, time.March, 6, 9), "today", 0},
+		{at(ny, 2026, time.March, 6, 23), "today", 0},
+		{at(ny, 2026, time.March, 7, 0), "tomorrow", 1},
+		{at(ny, 2026, time.March, 5, 12), "yesterday", -1},
+		{at(ny, 2026, time.March, 2, 12), "4d overdue", -4},
+		// Daylight saving time starts on March 8.
+		{at(ny, 2026, time.March, 8, 0), "Sun", 2},
+		{at(ny, 2026, time.March, 9, 0), "Mon", 3},
+		{at(ny, 2026, time.March, 13, 0), "Mar 13", 7},
+		{at(ny, 2027, time.January, 4, 0), "Jan 4, 2027", 304},
+		// Dates are read in the zone of now.
+		{at(time.UTC, 2026, time.March, 7, 3), "today", 0},
+	}
+	for _, tt := range tests {
+		label, days := relativeDate(tt.due, now)
+		if label != tt.label || days != tt.days {
+			t.Errorf("relativeDate(%v) = %q, %d, want %q, %d", tt.due, label, days, tt.label, tt.days)
+		}
+	}
+
+	// Two days after the switch to daylight saving time.
+	label, days := relativeDate(at(ny, 2026, time.March, 10, 0), at(ny, 2026, time.March, 8, 12))
+	if label != "Tue" || days != 2 {
+		t.Errorf("relativeDate across DST = %q, %d, want %q, 2", label, days, "Tue")
+	}
+	// And back in November.
+	if _, days := relativeDate(at(ny, 2026, time.November, 2, 0), at(ny, 2026, time.October, 31, 12)); days != 2 {
+		t.Errorf("relativeDate across the end of DST = %d days, want 2", days)
+	}
+}
+
+func TestTaskDelegateAdaptsToWidth(t *testing.T) {
+	noColor(t)
+	task := Task{ID: "86abc", Name: "Fix login", DateUpdated: "1"}
+	task.Status.Status = "in progress"
+	task.Priority = &TaskPriority{ID: "2"}
+	task.DueDate = strconv.FormatInt(time.Date(2030, time.January, 4, 12, 0, 0, 0, time.Local).UnixMilli(), 10)
+	task.Folder.Name, task.List.Name = "Backend", "Sprint 1"
+	task.Assignees = []Member{{Username: "ann lee"}}
+	task.Tags = []Tag{{Name: "bug"}}
+	stats := newTaskStats()
+	stats.counts[task.ID] = taskCounts{subtasks: 2, comments: 3, updated: "1"}
+	due, _ := relativeDate(time.Date(2030, time.January, 4, 12, 0, 0, 0, time.Local), time.Now())
+
+	// The delegate has 4 columns less than the list.
+	tests := []struct {
+		width int
+		shown []string
+		left  []string
+	}{
+		{44, []string{"●", "Fix login", "⚑", due, "AL"}, []string{"IN PROGRESS", "High", "Sprint 1", "#bug", "↳2"}},
+		{54, []string{"●", "⚑", "AL", "↳2 ✉3"}, []string{"IN PROGRESS", "High", "Sprint 1", "#bug"}},
+		{64, []string{"IN PROGRESS", "⚑", "AL", "↳2 ✉3"}, []string{"High", "Sprint 1", "#bug"}},
+		{74, []string{"IN PROGRESS", "Backend / Sprint 1", "AL"}, []string{"High", "#bug"}},
+		{84, []string{"⚑ High", "Backend / Sprint 1"}, []string{"#bug"}},
+		{94, []string{"IN PROGRESS", "Fix login", "⚑ High", due, "Backend / Sprint 1", "AL", "#bug", "↳2 ✉3"}, nil},
+	}
+	for _, tt := range tests {
+		d := newTaskDelegate(nil, false, 0, stats, time.Local)
+		l := newList([]list.Item{task}, d, tt.width, 20)
+		var b strings.Builder
+		d.Render(&b, l, 0, task)
+		out := b.String()
+		for _, line := range strings.Split(out, "\n") {
+			if w := lipgloss.Width(line); w > tt.width {
+				t.Errorf("width %d: line of %d columns: %q", tt.width, w, line)
+			}
+		}
+		for _, want := range tt.shown {
+			if !strings.Contains(out, want) {
+				t.Errorf("width %d: no %q in\n%s", tt.width, want, out)
+			}
+		}
+		for _, part := range tt.left {
+			if strings.Contains(out, part) {
+				t.Errorf("width %d: %q not left out of\n%s", tt.width, part, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+// --- TASK SHOW (CLI) ---
+
+var (
+	showComments int
+	showNoColor  bool
+	showOutput   string
+)
+
+var taskShowCmd = &cobra.Command{
+	Use:   "show <id|url|custom-id>",
+	Short: "Print a task",
+	Long: `Print a task with its description, checklists, subtasks and latest comments.
+The task can be given by ID, by URL (https://app.clickup.com/t/86abc123) or by
+custom task ID (ENG-1234). Output fits the terminal, or the preview window when
+used as an fzf preview.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if showOutput != "text" && showOutput != "json" {
+			fmt.Printf("Error: unknown output format %q, expected text or json.\n", showOutput)
+			os.Exit(1)
+		}
+		apiToken, teamID := requireCredentials()
+		var task Task
+		switch msg := fetchTaskRefCmd(apiToken, teamID, args[0])().(type) {
+		case error:
+			fmt.Println("Error fetching task:", msg)
+			os.Exit(1)
+		case Task:
+			task = msg
+		}
+		comments := []Comment{}
+		if showComments != 0 {
+			switch msg := fetchCommentsCmd(apiToken, task.ID)().(type) {
+			case error:
+				fmt.Println("Error fetching comments:", msg)
+				os.Exit(1)
+			case CommentsResponse:
+				comments = latestComments(msg.Comments, showComments)
+			}
+		}
+
+		if showOutput == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			err := enc.Encode(struct {
+				Task
+				Comments []Comment `json:"comments"`
+			}{task, comments})
+			if err != nil {
+				fmt.Println("Error encoding task:", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		switch {
+		case showNoColor || os.Getenv("NO_COLOR") != "":
+			lipgloss.SetColorProfile(termenv.Ascii)
+		case os.Getenv("FZF_PREVIEW_COLUMNS") != "":
+			// fzf previews are not terminals, but they do show colors.
+			lipgloss.SetColorProfile(termenv.ANSI256)
+		}
+		fmt.Println(renderTask(task, comments, outputWidth()))
+	},
+}
+
+// latestComments returns the n most recent comments, oldest first. ClickUp
+// lists comments newest first; a negative n keeps all of them.
+func latestComments(comments []Comment, n int) []Comment {
+	if n >= 0 && len(comments) > n {
+		comments = comments[:n]
+	}
+	latest := make([]Comment, len(comments))
+	for i, c := range comments {
+		latest[len(comments)-1-i] = c
+	}
+	return latest
+}
+
+// outputWidth is the width to wrap printed tasks at.
+func outputWidth() int {
+	for _, env := range []string{"FZF_PREVIEW_COLUMNS", "COLUMNS"} {
+		if w, err := strconv.Atoi(os.Getenv(env)); err == nil && w > 0 {
+			return w
+		}
+	}
+	if w, _, err := term.GetSize(os.Stdout.Fd()); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
+
+// renderTask formats a task for printing.
+func renderTask(task Task, comments []Comment, width int) string {
+	section := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.Accent))
+	label := blurredStyle
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(task.Name))
+	b.WriteString("\n\n")
+	field := func(name, value string) {
+		if value != "" {
+			b.WriteString(label.Render(fmt.Sprintf("%-10s", name)) + value + "\n")
+		}
+	}
+	id := task.ID
+	if task.CustomID != "" {
+		id = task.CustomID + " (" + task.ID + ")"
+	}
+	field("ID", id)
+	field("Status", task.Status.Status)
+	field("List", taskLocation(task))
+	var assignees []string
+	for _, a := range task.Assignees {
+		assignees = append(assignees, a.Username)
+	}
+	field("Assignees", strings.Join(assignees, ", "))
+	if task.Priority != nil {
+		field("Priority", task.Priority.Priority)
+	}
+	field("Tags", tagBadges(task.Tags))
+	for _, d := range []struct{ name, ms string }{
+		{"Start", task.StartDate},
+		{"Due", task.DueDate},
+		{"Created", task.DateCreated},
+		{"Updated", task.DateUpdated},
+	} {
+		if t, ok := msTime(d.ms); ok {
+			field(d.name, formatDate(t))
+		}
+	}
+	field("URL", task.URL)
+
+	if description := strings.TrimSpace(taskDescription(task)); description != "" {
+		b.WriteString("\n" + renderMarkdown(description, width) + "\n")
+	}
+
+	for _, cl := range task.Checklists {
+		done := 0
+		for _, item := range cl.Items {
+			if item.Resolved {
+				done++
+			}
+		}
+		b.WriteString("\n" + section.Render(fmt.Sprintf("%s (%d/%d)", cl.Name, done, len(cl.Items))) + "\n")
+		for _, item := range cl.Items {
+			box := "☐"
+			if item.Resolved {
+				box = "☑"
+			}
+			b.WriteString(hangingIndent(box+" ", item.Name, width) + "\n")
+		}
+	}
+
+	if len(task.Subtasks) > 0 {
+		b.WriteString("\n" + section.Render(fmt.Sprintf("Subtasks (%d)", len(task.Subtasks))) + "\n")
+		for _, sub := range task.Subtasks {
+			b.WriteString(hangingIndent("• ", sub.Name+" "+label.Render("["+sub.Status.Status+"] "+sub.ID), width) + "\n")
+		}
+	}
+
+	if len(comments) > 0 {
+		b.WriteString("\n" + section.Render("Comments") + "\n")
+		for _, c := range comments {
+			b.WriteString(label.Render(c.User.Username+" · "+formatDate(c.Time())) + "\n")
+			b.WriteString(ansi.Wrap(strings.TrimSpace(c.Text()), width, "") + "\n\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func init() {
+	taskShowCmd.Flags().IntVar(&showComments, "comments", 5, "number of latest comments to show; -1 for all")
+	taskShowCmd.Flags().BoolVar(&showNoColor, "no-color", false, "print without colors (also set by $NO_COLOR)")
+	taskShowCmd.Flags().StringVarP(&showOutput, "output", "o", "text", "output format: text or json")
+}
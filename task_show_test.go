@@ -0,0 +1,145 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// noColor renders without styles for the rest of the test.
+func noColor(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.Ascii)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	noColor(t)
+	tests := []struct {
+		name  string
+		md    string
+		width int
+		want  string
+	}{
+		{"heading and paragraph", "## Steps\r\nSee **the** _docs_ and ~~not~~ this.", 40,
+			"Steps\nSee the docs and not this."},
+		{"lists", "- one\n* two\n  + nested\n1. first\n2) second", 40,
+			"• one\n• two\n  • nested\n1. first\n2) second"},
+		{"checkboxes", "- [ ] open\n- [x] done\n- [X] also done\n* [ ] star", 40,
+			"☐ open\n☑ done\n☑ also done\n☐ star"},
+		{"wrapped item", "- a long item that wraps around", 16,
+			"• a long item\n  that wraps\n  around"},
+		{"wrapped paragraph", "a long line that wraps around", 12,
+			"a long line\nthat wraps\naround"},
+		{"code fence", "Run:\n```sh\ngo test **./...**\n  - not a list\n```\nDone", 40,
+			"Run:\n    go test **./...**\n      - not a list\nDone"},
+		{"quote", "> quoted *text*", 40, "│ quoted text"},
+		{"rule", "text\n\n---\n", 10, "text\n\n──────────"},
+		{"trailing blank lines", "text\n\n\n", 40, "text"},
+	}
+	for _, tt := range tests {
+		if got := renderMarkdown(tt.md, tt.width); got != tt.want {
+			t.Errorf("%s: renderMarkdown(%q) =\n%s\nwant\n%s", tt.name, tt.md, got, tt.want)
+		}
+	}
+}
+
+func TestRenderInline(t *testing.T) {
+	noColor(t)
+	tests := []struct {
+		text, want string
+	}{
+		{"plain", "plain"},
+		{"**bold** and *italic* and __bold__ and _italic_", "bold and italic and bold and italic"},
+		{"snake_case_name stays", "snake_case_name stays"},
+		{"[**bold** link](https://x.io)", "bold link (https://x.io)"},
+		{"[*italic* link](https://x.io) after", "italic link (https://x.io) after"},
+		{"[https://x.io](https://x.io)", "https://x.io"},
+		{"`**code**` and **bold**", "**code** and bold"},
+		{"an `unclosed code span", "an `unclosed code span"},
+	}
+	for _, tt := range tests {
+		if got := renderInline(tt.text); got != tt.want {
+			t.Errorf("renderInline(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestHangingIndent(t *testing.T) {
+	tests := []struct {
+		prefix, text string
+		width        int
+		want         string
+	}{
+		{"• ", "short", 40, "• short"},
+		{"• ", "one two three four", 12, "• one two\n  three four"},
+		{"12. ", "one two three four", 14, "12. one two\n    three four"},
+		// Never wraps narrower than 10 columns after the prefix.
+		{"• ", "one two three four", 4, "• one two\n  three four"},
+	}
+	for _, tt := range tests {
+		if got := hangingIndent(tt.prefix, tt.text, tt.width); got != tt.want {
+			t.Errorf("hangingIndent(%q, %q, %d) =\n%s\nwant\n%s", tt.prefix, tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestLatestComments(t *testing.T) {
+	// ClickUp lists comments newest first.
+	comments := []Comment{{ID: "3"}, {ID: "2"}, {ID: "1"}}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{0, nil},
+		{2, []string{"2", "3"}},
+		{3, []string{"1", "2", "3"}},
+		{5, []string{"1", "2", "3"}},
+		{-1, []string{"1", "2", "3"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range latestComments(comments, tt.n) {
+			got = append(got, c.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("latestComments(n=%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTaskComments(t *testing.T) {
+	noColor(t)
+	task := Task{ID: "86abc", Name: "Fix login", MarkdownContent: "- [ ] check **Safari**"}
+	comments := []Comment{{ID: "3"}, {ID: "2"}, {ID: "1"}}
+	for i := range comments {
+		comments[i].User.Username = "ann"
+		comments[i].Date = "1700000000000"
+		comments[i].Comment = []struct {
+			Text string `json:"text"`
+		}{{Text: "comment " + comments[i].ID}}
+	}
+
+	out := renderTask(task, latestComments(comments, 0), 40)
+	if strings.Contains(out, "Comments") {
+		t.Errorf("--comments 0 shows a comments section:\n%s", out)
+	}
+	if !strings.Contains(out, "☐ check Safari") {
+		t.Errorf("description not rendered as Markdown:\n%s", out)
+	}
+
+	out = renderTask(task, latestComments(comments, 2), 40)
+	first, second := strings.Index(out, "comment 2"), strings.Index(out, "comment 3")
+	if strings.Contains(out, "comment 1") || first < 0 || second < first {
+		t.Errorf("--comments 2 does not show the latest two, oldest first:\n%s", out)
+	}
+
+	out = renderTask(task, latestComments(comments, -1), 40)
+	if !strings.HasSuffix(out, "Comments\nann · "+formatDate(comments[2].Time())+"\ncomment 1\n\nann · "+
+		formatDate(comments[1].Time())+"\ncomment 2\n\nann · "+formatDate(comments[0].Time())+"\ncomment 3") {
+		t.Errorf("--comments -1 does not end with all comments:\n%s", out)
+	}
+}
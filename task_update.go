@@ -0,0 +1,704 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// --- BULK UPDATE (CLI) ---
+
+var (
+	updateWhere       string
+	updateIDs         string
+	updateSets        []string
+	updateDryRun      bool
+	updateConcurrency int
+)
+
+var taskUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update many tasks at once",
+	Long: `Update every task matching a query (--where) or a list of task IDs (--ids).
+
+Query terms are space separated key=value pairs; use commas for several values:
+  status, assignee (me, a user ID, username or email), list, folder, space, tag,
+  priority
+
+Changes are given with --set key=value and may be repeated:
+  status=<name>          priority=urgent|high|normal|low|none
+  name=<text>            list=<list ID> (move the task)
+  assignee=+me,-bob      tag=+bug,-triage
+  due=<date>             start=<date>
+
+Dates are read in the time zone of your ClickUp profile: today, tomorrow,
+fri, next fri, in 3d, +2w, 2026-11-01, Nov 1, optionally followed by a time
+such as 9am or 14:30. none clears a date.`,
+	Example: `  clup task update --where 'status=review assignee=me' --set status=done
+  clup task update --ids ENG-12 --set due='next fri 17:00' --set start=today
+  printf '86abc123\n86abc124\n' | clup task update --ids - --set priority=high --dry-run`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if (updateWhere == "") == (updateIDs == "") {
+			fmt.Println("Exactly one of --where or --ids is required.")
+			os.Exit(1)
+		}
+		if len(updateSets) == 0 {
+			fmt.Println("Nothing to change, use --set key=value.")
+			os.Exit(1)
+		}
+		apiToken, teamID := requireCredentials()
+		members := &memberResolver{apiToken: apiToken, teamID: teamID}
+
+		update, err := parseTaskUpdate(updateSets, members)
+		if err != nil {
+			fmt.Println("Error parsing --set:", err)
+			os.Exit(1)
+		}
+
+		if update.listID != "" {
+			if update.listName, err = fetchListName(apiToken, update.listID); err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+		}
+
+		var tasks []Task
+		var missing []bulkResult
+		if updateWhere != "" {
+			tasks, err = queryTasks(apiToken, teamID, updateWhere, members)
+		} else {
+			tasks, missing, err = tasksByIDs(apiToken, teamID, updateIDs, os.Stdin, updateConcurrency)
+		}
+		if err != nil {
+			fmt.Println("Error fetching tasks:", err)
+			os.Exit(1)
+		}
+
+		var changes []taskChange
+		for _, task := range tasks {
+			if change := update.plan(task, members); len(change.diffs) > 0 {
+				changes = append(changes, change)
+			}
+		}
+		unchanged := len(tasks) - len(changes)
+
+		if updateDryRun {
+			printChanges(os.Stdout, changes)
+			fmt.Printf("\n%d task(s) would be updated, %d unchanged, %d not found.\n", len(changes), unchanged, len(missing))
+			printFailures(os.Stdout, missing)
+			if len(missing) > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+
+		failures := applyChanges(apiToken, teamID, changes, updateConcurrency)
+		fmt.Printf("Updated %d task(s), %d unchanged, %d failed.\n", len(changes)-len(failures), unchanged, len(failures)+len(missing))
+		failures = append(missing, failures...)
+		printFailures(os.Stdout, failures)
+		if len(failures) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	taskUpdateCmd.Flags().StringVar(&updateWhere, "where", "", "query selecting the tasks to update")
+	taskUpdateCmd.Flags().StringVar(&updateIDs, "ids", "", "comma separated task IDs, URLs or custom IDs, or - to read them from stdin")
+	taskUpdateCmd.Flags().StringArrayVar(&updateSets, "set", nil, "change to apply as key=value (repeatable)")
+	taskUpdateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "print the planned changes without applying them")
+	taskUpdateCmd.Flags().IntVar(&updateConcurrency, "concurrency", 4, "number of tasks updated in parallel")
+}
+
+// splitQuery splits s on whitespace, keeping quoted sections together.
+func splitQuery(s string) []string {
+	var fields []string
+	var current strings.Builder
+	var quote rune
+	inField := false
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inField = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+func splitValues(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// memberResolver maps "me", user IDs, usernames and emails to user IDs. The
+// workspace members are only fetched when a name has to be looked up.
+type memberResolver struct {
+	apiToken string
+	teamID   string
+	me       *User
+	members  []Member
+	loaded   bool
+}
+
+func (r *memberResolver) resolve(s string) (int, error) {
+	if strings.EqualFold(s, "me") {
+		if r.me == nil {
+			switch msg := fetchUserCmd(r.apiToken)().(type) {
+			case error:
+				return 0, msg
+			case UserResponse:
+				r.me = &msg.User
+			}
+		}
+		return r.me.ID, nil
+	}
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, nil
+	}
+	if err := r.load(); err != nil {
+		return 0, err
+	}
+	for _, member := range r.members {
+		if strings.EqualFold(member.Username, s) || strings.EqualFold(member.Email, s) {
+			return member.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown member %q", s)
+}
+
+func (r *memberResolver) load() error {
+	if r.loaded {
+		return nil
+	}
+	switch msg := fetchTeamsCmd(r.apiToken)().(type) {
+	case error:
+		return msg
+	case TeamsResponse:
+		for _, team := range msg.Teams {
+			if team.ID != r.teamID {
+				continue
+			}
+			for _, member := range team.Members {
+				r.members = append(r.members, member.User)
+			}
+		}
+	}
+	r.loaded = true
+	return nil
+}
+
+// name returns a display name for a user ID, falling back to the ID itself.
+func (r *memberResolver) name(id int) string {
+	if r.me != nil && r.me.ID == id {
+		return r.me.Username
+	}
+	if r.load() == nil {
+		for _, member := range r.members {
+			if member.ID == id {
+				return member.Username
+			}
+		}
+	}
+	return strconv.Itoa(id)
+}
+
+// location is the time zone of the user, in which dates are read.
+func (r *memberResolver) location() (*time.Location, error) {
+	if _, err := r.resolve("me"); err != nil {
+		return nil, err
+	}
+	return userLocation(r.me), nil
+}
+
+// queryTasks fetches the tasks matching a --where expression.
+func queryTasks(apiToken, teamID, where string, members *memberResolver) ([]Task, error) {
+	query := neturl.Values{}
+	query.Set("subtasks", "true")
+	var priorityFilter []string
+	for _, term := range splitQuery(where) {
+		k, v, ok := strings.Cut(term, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid query term %q, expected key=value", term)
+		}
+		values := splitValues(v)
+		switch strings.ToLower(k) {
+		case "status":
+			for _, status := range values {
+				query.Add("statuses[]", status)
+			}
+			query.Set("include_closed", "true")
+		case "assignee":
+			for _, name := range values {
+				id, err := members.resolve(name)
+				if err != nil {
+					return nil, err
+				}
+				query.Add("assignees[]", strconv.Itoa(id))
+			}
+		case "list":
+			for _, id := range values {
+				query.Add("list_ids[]", id)
+			}
+		case "folder":
+			for _, id := range values {
+				query.Add("project_ids[]", id)
+			}
+		case "space":
+			for _, id := range values {
+				query.Add("space_ids[]", id)
+			}
+		case "tag":
+			for _, tag := range values {
+				query.Add("tags[]", tag)
+			}
+		case "priority":
+			for _, p := range values {
+				if _, err := parsePriority(p); err != nil {
+					return nil, err
+				}
+				priorityFilter = append(priorityFilter, p)
+			}
+		default:
+			return nil, fmt.Errorf("unknown query key %q", k)
+		}
+	}
+
+	msg := fetchFilteredTasksCmd(apiToken, teamID, query)()
+	if err, ok := msg.(error); ok {
+		return nil, err
+	}
+	tasks := msg.(TasksResponse).Tasks
+	if len(priorityFilter) == 0 {
+		return tasks, nil
+	}
+	var filtered []Task
+	for _, task := range tasks {
+		for _, p := range priorityFilter {
+			want, _ := parsePriority(p)
+			if taskPriorityValue(task) == want {
+				filtered = append(filtered, task)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// tasksByIDs fetches the tasks listed in ids, or read from stdin when ids is
+// "-". Tasks can be given by ID, URL or custom ID. Only the first field of
+// every line is used, so lines like "86abc123 Fix login" work as well. Tasks
+// that can't be fetched are returned as failures, with the reference as ID.
+func tasksByIDs(apiToken, teamID, ids string, stdin io.Reader, concurrency int) ([]Task, []bulkResult, error) {
+	var taskIDs []string
+	if ids == "-" {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) > 0 {
+				taskIDs = append(taskIDs, strings.Trim(fields[0], "[]"))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		taskIDs = splitValues(ids)
+	}
+
+	results := make([]bulkResult, len(taskIDs))
+	forEachConcurrent(len(taskIDs), concurrency, func(i int) {
+		switch msg := fetchTaskRefCmd(apiToken, teamID, taskIDs[i])().(type) {
+		case error:
+			results[i] = bulkResult{task: Task{ID: taskIDs[i]}, err: msg}
+		case Task:
+			results[i] = bulkResult{task: msg}
+		}
+	})
+	var tasks []Task
+	var failures []bulkResult
+	for _, r := range results {
+		if r.err != nil {
+			failures = append(failures, r)
+		} else {
+			tasks = append(tasks, r.task)
+		}
+	}
+	return tasks, failures, nil
+}
+
+// fetchListName looks up the list tasks are moved to.
+func fetchListName(apiToken, listID string) (string, error) {
+	body, err := sendContainerRequest(apiToken, "GET", containerURL(taskScope{kind: scopeList, id: listID}), nil)
+	if err != nil {
+		return "", fmt.Errorf("fetch list %s failed: %v", listID, err)
+	}
+	var l struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &l); err != nil {
+		return "", err
+	}
+	return l.Name, nil
+}
+
+// forEachConcurrent calls fn for 0..n-1 with at most limit calls in flight.
+func forEachConcurrent(n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func parsePriority(s string) (int, error) {
+	if v, err := strconv.Atoi(s); err == nil && v >= 0 && v <= 4 {
+		return v, nil
+	}
+	for _, item := range priorities {
+		p := item.(Priority)
+		if strings.EqualFold(p.Name, s) {
+			return p.Value, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q", s)
+}
+
+func priorityName(value int) string {
+	for _, item := range priorities {
+		if p := item.(Priority); p.Value == value {
+			return p.Name
+		}
+	}
+	return "None"
+}
+
+func taskPriorityValue(t Task) int {
+	if t.Priority == nil {
+		return 0
+	}
+	v, _ := strconv.Atoi(t.Priority.ID)
+	return v
+}
+
+// taskUpdate is the parsed form of the --set flags.
+type taskUpdate struct {
+	status       *string
+	name         *string
+	priority     *int
+	listID       string
+	listName     string
+	addAssignees []int
+	remAssignees []int
+	addTags      []string
+	remTags      []string
+	due          *taskDate
+	start        *taskDate
+}
+
+func parseTaskUpdate(sets []string, members *memberResolver) (taskUpdate, error) {
+	var u taskUpdate
+	for _, set := range sets {
+		k, v, ok := strings.Cut(set, "=")
+		if !ok {
+			return u, fmt.Errorf("invalid change %q, expected key=value", set)
+		}
+		switch k = strings.ToLower(k); k {
+		case "status":
+			u.status = &v
+		case "due", "start":
+			loc, err := members.location()
+			if err != nil {
+				return u, err
+			}
+			d, err := parseDate(v, time.Now().In(loc))
+			if err != nil {
+				return u, err
+			}
+			if k == "due" {
+				u.due = &d
+			} else {
+				u.start = &d
+			}
+		case "name":
+			u.name = &v
+		case "priority":
+			p, err := parsePriority(v)
+			if err != nil {
+				return u, err
+			}
+			u.priority = &p
+		case "list":
+			u.listID = v
+		case "assignee", "assignees":
+			for _, name := range splitValues(v) {
+				remove := strings.HasPrefix(name, "-")
+				id, err := members.resolve(strings.TrimLeft(name, "+-"))
+				if err != nil {
+					return u, err
+				}
+				if remove {
+					u.remAssignees = append(u.remAssignees, id)
+				} else {
+					u.addAssignees = append(u.addAssignees, id)
+				}
+			}
+		case "tag", "tags":
+			for _, tag := range splitValues(v) {
+				if strings.HasPrefix(tag, "-") {
+					u.remTags = append(u.remTags, strings.TrimPrefix(tag, "-"))
+				} else {
+					u.addTags = append(u.addTags, strings.TrimPrefix(tag, "+"))
+				}
+			}
+		default:
+			return u, fmt.Errorf("unknown field %q", k)
+		}
+	}
+	return u, nil
+}
+
+type fieldDiff struct {
+	field, before, after string
+}
+
+// taskChange holds the API calls needed to bring one task in line with a
+// taskUpdate, together with a human readable diff.
+type taskChange struct {
+	task    Task
+	fields  map[string]interface{}
+	listID  string
+	addTags []string
+	remTags []string
+	diffs   []fieldDiff
+}
+
+func (u taskUpdate) plan(task Task, members *memberResolver) taskChange {
+	c := taskChange{task: task, fields: map[string]interface{}{}}
+	if u.status != nil && !strings.EqualFold(task.Status.Status, *u.status) {
+		c.fields["status"] = *u.status
+		c.diffs = append(c.diffs, fieldDiff{"status", task.Status.Status, *u.status})
+	}
+	if u.name != nil && task.Name != *u.name {
+		c.fields["name"] = *u.name
+		c.diffs = append(c.diffs, fieldDiff{"name", task.Name, *u.name})
+	}
+	if u.priority != nil && taskPriorityValue(task) != *u.priority {
+		if *u.priority > 0 {
+			c.fields["priority"] = *u.priority
+		} else {
+			c.fields["priority"] = nil
+		}
+		c.diffs = append(c.diffs, fieldDiff{"priority", priorityName(taskPriorityValue(task)), priorityName(*u.priority)})
+	}
+	for _, d := range []struct {
+		name   string
+		want   *taskDate
+		before string
+	}{{"due", u.due, task.DueDate}, {"start", u.start, task.StartDate}} {
+		if d.want == nil || d.want.sameAs(d.before) {
+			continue
+		}
+		setDateField(c.fields, d.name, *d.want)
+		loc, _ := members.location()
+		c.diffs = append(c.diffs, fieldDiff{d.name, taskDateOf(d.before, loc).String(), d.want.String()})
+	}
+	if u.listID != "" && task.List.ID != u.listID {
+		c.listID = u.listID
+		after := u.listName
+		if after == "" {
+			after = u.listID
+		}
+		c.diffs = append(c.diffs, fieldDiff{"list", task.List.Name, after})
+	}
+
+	assigned := map[int]bool{}
+	var before []string
+	for _, a := range task.Assignees {
+		assigned[a.ID] = true
+		before = append(before, a.Username)
+	}
+	var add, rem []int
+	for _, id := range u.addAssignees {
+		if !assigned[id] {
+			add = append(add, id)
+			assigned[id] = true
+		}
+	}
+	for _, id := range u.remAssignees {
+		if assigned[id] {
+			rem = append(rem, id)
+			delete(assigned, id)
+		}
+	}
+	if len(add) > 0 || len(rem) > 0 {
+		changed := map[string][]int{}
+		if len(add) > 0 {
+			changed["add"] = add
+		}
+		if len(rem) > 0 {
+			changed["rem"] = rem
+		}
+		c.fields["assignees"] = changed
+		var after []string
+		for id := range assigned {
+			after = append(after, members.name(id))
+		}
+		sort.Strings(after)
+		c.diffs = append(c.diffs, fieldDiff{"assignees", strings.Join(before, ", "), strings.Join(after, ", ")})
+	}
+
+	tagged := map[string]bool{}
+	var tagsBefore []string
+	for _, t := range task.Tags {
+		tagged[strings.ToLower(t.Name)] = true
+		tagsBefore = append(tagsBefore, t.Name)
+	}
+	for _, tag := range u.addTags {
+		if !tagged[strings.ToLower(tag)] {
+			c.addTags = append(c.addTags, tag)
+			tagged[strings.ToLower(tag)] = true
+		}
+	}
+	for _, tag := range u.remTags {
+		if tagged[strings.ToLower(tag)] {
+			c.remTags = append(c.remTags, tag)
+			delete(tagged, strings.ToLower(tag))
+		}
+	}
+	if len(c.addTags) > 0 || len(c.remTags) > 0 {
+		var tagsAfter []string
+		for tag := range tagged {
+			tagsAfter = append(tagsAfter, tag)
+		}
+		sort.Strings(tagsAfter)
+		c.diffs = append(c.diffs, fieldDiff{"tags", strings.Join(tagsBefore, ", "), strings.Join(tagsAfter, ", ")})
+	}
+	return c
+}
+
+func printChanges(w io.Writer, changes []taskChange) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TASK\tNAME\tFIELD\tBEFORE\tAFTER")
+	for _, c := range changes {
+		for i, d := range c.diffs {
+			id, name := c.task.ID, truncate(c.task.Name, 40)
+			if i > 0 {
+				id, name = "", ""
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", id, name, d.field, orDash(d.before), orDash(d.after))
+		}
+	}
+	tw.Flush()
+}
+
+// printFailures lists the tasks that could not be fetched or updated.
+func printFailures(w io.Writer, failures []bulkResult) {
+	for _, f := range failures {
+		if f.task.Name == "" {
+			fmt.Fprintf(w, "  ✗ [%s]: %v\n", f.task.ID, f.err)
+		} else {
+			fmt.Fprintf(w, "  ✗ [%s] %s: %v\n", f.task.ID, f.task.Name, f.err)
+		}
+	}
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// applyChanges runs the planned changes with bounded concurrency and returns
+// the tasks that failed.
+func applyChanges(apiToken, teamID string, changes []taskChange, concurrency int) []bulkResult {
+	results := make([]bulkResult, len(changes))
+	forEachConcurrent(len(changes), concurrency, func(i int) {
+		results[i] = bulkResult{task: changes[i].task, err: applyChange(apiToken, teamID, changes[i])}
+	})
+	var failures []bulkResult
+	for _, r := range results {
+		if r.err != nil {
+			failures = append(failures, r)
+		}
+	}
+	return failures
+}
+
+func applyChange(apiToken, teamID string, c taskChange) error {
+	if len(c.fields) > 0 {
+		if err, ok := updateTaskFieldsCmd(apiToken, c.task.ID, c.fields)().(error); ok {
+			return err
+		}
+	}
+	if c.listID != "" {
+		if err, ok := moveTaskCmd(apiToken, teamID, c.task.ID, c.listID)().(error); ok {
+			return err
+		}
+	}
+	for _, tag := range c.addTags {
+		if err, ok := addTagCmd(apiToken, c.task.ID, tag)().(error); ok {
+			return err
+		}
+	}
+	for _, tag := range c.remTags {
+		if err, ok := removeTagCmd(apiToken, c.task.ID, tag)().(error); ok {
+			return err
+		}
+	}
+	return nil
+}
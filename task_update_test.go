@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitQuery(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"status=review", []string{"status=review"}},
+		{"  status=review \t assignee=me\n", []string{"status=review", "assignee=me"}},
+		{"status='in progress,review' tag=bug", []string{"status=in progress,review", "tag=bug"}},
+		{`status="in progress"`, []string{"status=in progress"}},
+		{`name="it's fine"`, []string{"name=it's fine"}},
+		{"status=''", []string{"status="}},
+		{"status='in progress", []string{"status=in progress"}},
+	}
+	for _, tt := range tests {
+		if got := splitQuery(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitValues(t *testing.T) {
+	got := splitValues(" a, b ,,c ")
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitValues = %q, want %q", got, want)
+	}
+}
+
+func TestParseTaskUpdate(t *testing.T) {
+	members := &memberResolver{
+		me:      &User{ID: 1, Username: "me"},
+		members: []Member{{ID: 2, Username: "ann", Email: "ann@example.com"}, {ID: 3, Username: "bob"}},
+		loaded:  true,
+	}
+	str := func(s string) *string { return &s }
+	num := func(i int) *int { return &i }
+	tests := []struct {
+		sets    []string
+		want    taskUpdate
+		wantErr bool
+	}{
+		{sets: []string{"status=done"}, want: taskUpdate{status: str("done")}},
+		{sets: []string{"STATUS=in progress"}, want: taskUpdate{status: str("in progress")}},
+		{sets: []string{"name=a=b"}, want: taskUpdate{name: str("a=b")}},
+		{sets: []string{"priority=high"}, want: taskUpdate{priority: num(2)}},
+		{sets: []string{"priority=0"}, want: taskUpdate{priority: num(0)}},
+		{sets: []string{"list=901"}, want: taskUpdate{listID: "901"}},
+		{
+			sets: []string{"assignee=me,+ann,-bob,-42"},
+			want: taskUpdate{addAssignees: []int{1, 2}, remAssignees: []int{3, 42}},
+		},
+		{sets: []string{"assignees=ann@example.com"}, want: taskUpdate{addAssignees: []int{2}}},
+		{
+			sets: []string{"tag=bug,+triage", "tags=-wontfix"},
+			want: taskUpdate{addTags: []string{"bug", "triage"}, remTags: []string{"wontfix"}},
+		},
+		{sets: []string{"status"}, wantErr: true},
+		{sets: []string{"colour=red"}, wantErr: true},
+		{sets: []string{"priority=highest"}, wantErr: true},
+		{sets: []string{"assignee=carol"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseTaskUpdate(tt.sets, members)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseTaskUpdate(%q) succeeded, want an error", tt.sets)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTaskUpdate(%q): %v", tt.sets, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTaskUpdate(%q) = %+v, want %+v", tt.sets, got, tt.want)
+		}
+	}
+}
+
+func TestTasksByIDs(t *testing.T) {
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/task/")
+		if id == "gone" {
+			http.Error(w, `{"err":"Task not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%q,"name":"Task %s"}`, id, id)
+	}))
+	stdin := strings.NewReader("86abc1 Fix login\n\ngone\n[86abc2] Other\n")
+	tasks, failures, err := tasksByIDs("pk_1", "1", "-", stdin, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	if want := []string{"86abc1", "86abc2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("tasks = %q, want %q", ids, want)
+	}
+	if len(failures) != 1 || failures[0].task.ID != "gone" || !strings.Contains(failures[0].err.Error(), "Task not found") {
+		t.Errorf("failures = %+v, want gone: Task not found", failures)
+	}
+
+	var out strings.Builder
+	printFailures(&out, failures)
+	if !strings.HasPrefix(out.String(), "  ✗ [gone]: fetch task gone failed") {
+		t.Errorf("printFailures = %q", out.String())
+	}
+}
+
+func TestPlanListChange(t *testing.T) {
+	task := Task{ID: "1"}
+	task.List.ID, task.List.Name = "900", "Backlog"
+	members := &memberResolver{loaded: true}
+	tests := []struct {
+		update taskUpdate
+		want   []fieldDiff
+	}{
+		{taskUpdate{listID: "900", listName: "Backlog"}, nil},
+		{taskUpdate{listID: "901", listName: "Sprint"}, []fieldDiff{{"list", "Backlog", "Sprint"}}},
+		{taskUpdate{listID: "901"}, []fieldDiff{{"list", "Backlog", "901"}}},
+	}
+	for _, tt := range tests {
+		if got := tt.update.plan(task, members).diffs; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("plan(%+v) = %+v, want %+v", tt.update, got, tt.want)
+		}
+	}
+}
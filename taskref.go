@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- TASK REFERENCES ---
+
+// taskRef identifies a task by its ClickUp ID or by a custom task ID such as
+// ENG-1234.
+type taskRef struct {
+	ID     string
+	Custom bool
+}
+
+var (
+	customTaskID = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9]+$`)
+	rawTaskID    = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+)
+
+// parseTaskRef accepts a task URL (https://app.clickup.com/t/86abc123 or
+// https://app.clickup.com/t/<team>/ENG-1234), a task ID, optionally written
+// as #86abc123 or CU-86abc123, or a custom task ID.
+func parseTaskRef(s string) (taskRef, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "://") {
+		u, err := neturl.Parse(s)
+		if err != nil {
+			return taskRef{}, fmt.Errorf("invalid task URL %q: %v", s, err)
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) < 2 || parts[0] != "t" {
+			return taskRef{}, fmt.Errorf("%q is not a ClickUp task URL", s)
+		}
+		s = parts[len(parts)-1]
+	}
+	s = strings.TrimPrefix(s, "#")
+	// CU-86abc123 is how ClickUp writes its own IDs, so it is never a
+	// custom task ID.
+	if id, ok := strings.CutPrefix(s, "CU-"); ok {
+		s = id
+	} else if customTaskID.MatchString(s) {
+		return taskRef{ID: s, Custom: true}, nil
+	}
+	if !rawTaskID.MatchString(s) {
+		return taskRef{}, fmt.Errorf("%q is not a task ID, custom task ID or task URL", s)
+	}
+	return taskRef{ID: s}, nil
+}
+
+// taskURL returns the API URL of a task; custom task IDs are looked up in
+// the given team.
+func (r taskRef) taskURL(teamID string, query neturl.Values) string {
+	if query == nil {
+		query = neturl.Values{}
+	}
+	if r.Custom {
+		query.Set("custom_task_ids", "true")
+		query.Set("team_id", teamID)
+	}
+	u := "https://api.clickup.com/api/v2/task/" + neturl.PathEscape(r.ID)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// fetchTaskRefCmd loads a task from a reference as accepted by parseTaskRef,
+// with its subtasks and Markdown description.
+func fetchTaskRefCmd(apiToken, teamID, ref string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := parseTaskRef(ref)
+		if err != nil {
+			return err
+		}
+		query := neturl.Values{}
+		query.Set("include_subtasks", "true")
+		query.Set("include_markdown_description", "true")
+		req, err := http.NewRequest("GET", r.taskURL(teamID, query), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", apiToken)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("fetch task %s failed: %s", r.ID, string(body))
+		}
+		var task Task
+		if err := json.Unmarshal(body, &task); err != nil {
+			return err
+		}
+		return task
+	}
+}
+
+// resolveTaskID returns the ClickUp ID of a task reference, looking up custom
+// task IDs and URLs with custom IDs.
+func resolveTaskID(apiToken, teamID, ref string) (string, error) {
+	r, err := parseTaskRef(ref)
+	if err != nil {
+		return "", err
+	}
+	if !r.Custom {
+		return r.ID, nil
+	}
+	switch msg := fetchTaskRefCmd(apiToken, teamID, ref)().(type) {
+	case error:
+		return "", msg
+	case Task:
+		return msg.ID, nil
+	}
+	return "", fmt.Errorf("task %s not found", ref)
+}
+
+// mustFetchTaskRef loads a task for a command, exiting when it can't be
+// found.
+func mustFetchTaskRef(apiToken, teamID, ref string) Task {
+	switch msg := fetchTaskRefCmd(apiToken, teamID, ref)().(type) {
+	case error:
+		fmt.Println("Error fetching task:", msg)
+		os.Exit(1)
+	case Task:
+		return msg
+	}
+	return Task{}
+}
+
+// --- UPDATE & VIEW (GO TO TASK) ---
+
+type gotoTaskMsg Task
+
+type gotoTaskErrMsg struct{ err error }
+
+// startGotoTask asks for a task to open from the task list. With ":" the
+// prompt is a command line that takes `goto <task>`.
+func (m model) startGotoTask(commandLine bool) (model, tea.Cmd) {
+	m.state = gotoTaskView
+	m.gotoErr = nil
+	m.gotoInput = textinput.New()
+	m.gotoInput.Placeholder = "task URL, ID or custom ID"
+	m.gotoInput.Width = 50
+	if commandLine {
+		m.gotoInput.Prompt = ":"
+		m.gotoInput.Placeholder = "goto <task>"
+	}
+	m.gotoInput.Focus()
+	return m, textinput.Blink
+}
+
+// gotoTaskCmd loads the task a reference points to for the go to prompt.
+func gotoTaskCmd(apiToken, teamID, ref string) tea.Cmd {
+	return func() tea.Msg {
+		switch msg := fetchTaskRefCmd(apiToken, teamID, ref)().(type) {
+		case error:
+			return gotoTaskErrMsg{msg}
+		case Task:
+			return gotoTaskMsg(msg)
+		}
+		return nil
+	}
+}
+
+func updateGotoTask(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case gotoTaskMsg:
+		m.loading = false
+		return m.openTaskDetail(msg.ID)
+	case gotoTaskErrMsg:
+		m.loading = false
+		m.gotoErr = msg.err
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.state = listView
+			return m, nil
+		case tea.KeyEnter:
+			ref := strings.TrimSpace(m.gotoInput.Value())
+			for _, command := range []string{"goto ", "g "} {
+				ref = strings.TrimSpace(strings.TrimPrefix(ref, command))
+			}
+			if ref == "" {
+				return m, nil
+			}
+			if _, err := parseTaskRef(ref); err != nil {
+				m.gotoErr = err
+				return m, nil
+			}
+			m.gotoErr = nil
+			m.loading = true
+			return m, gotoTaskCmd(m.apiToken, m.teamID, ref)
+		}
+	}
+	m.gotoInput, cmd = m.gotoInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewGotoTask() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Go to task"))
+	b.WriteString("\n\n")
+	b.WriteString(m.gotoInput.View())
+	switch {
+	case m.loading:
+		b.WriteString("\n\n" + helpStyle.Render("Loading..."))
+	case m.gotoErr != nil:
+		b.WriteString("\n\n" + failureStyle.Render("✗ "+m.gotoErr.Error()))
+	}
+	b.WriteString(helpStyle.Render("\n\nenter to open • esc to cancel"))
+	return appStyle.Render(b.String())
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestParseTaskRef(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    taskRef
+		wantErr bool
+	}{
+		{in: "86abc123", want: taskRef{ID: "86abc123"}},
+		{in: " #86abc123 ", want: taskRef{ID: "86abc123"}},
+		{in: "CU-86abc123", want: taskRef{ID: "86abc123"}},
+		{in: "CU-12345678", want: taskRef{ID: "12345678"}},
+		{in: "#CU-86abc123", want: taskRef{ID: "86abc123"}},
+		{in: "ENG-1234", want: taskRef{ID: "ENG-1234", Custom: true}},
+		{in: "#eng2-7", want: taskRef{ID: "eng2-7", Custom: true}},
+		{in: "https://app.clickup.com/t/86abc123", want: taskRef{ID: "86abc123"}},
+		{in: "https://app.clickup.com/t/9012345/ENG-1234", want: taskRef{ID: "ENG-1234", Custom: true}},
+		{in: "https://app.clickup.com/t/86abc123?comment=1", want: taskRef{ID: "86abc123"}},
+		{in: "https://app.clickup.com/v/l/abc", wantErr: true},
+		{in: "ENG-", wantErr: true},
+		{in: "CU-", wantErr: true},
+		{in: "86abc 123", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseTaskRef(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseTaskRef(%q) = %+v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseTaskRef(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestTaskURL(t *testing.T) {
+	if got, want := (taskRef{ID: "86abc123"}).taskURL("9", nil), "https://api.clickup.com/api/v2/task/86abc123"; got != want {
+		t.Errorf("taskURL = %q, want %q", got, want)
+	}
+	if got, want := (taskRef{ID: "ENG-1234", Custom: true}).taskURL("9", nil), "https://api.clickup.com/api/v2/task/ENG-1234?custom_task_ids=true&team_id=9"; got != want {
+		t.Errorf("taskURL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,552 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// --- TASK TEMPLATES ---
+
+// taskTemplate is a reusable task skeleton loaded from the templates
+// directory. Text fields may contain {{placeholders}} that are filled in
+// before the task is created.
+type taskTemplate struct {
+	Key       string        `yaml:"-"`
+	Name      string        `yaml:"name"`
+	Body      string        `yaml:"description"`
+	Status    string        `yaml:"status"`
+	Assignees []string      `yaml:"assignees"`
+	Priority  string        `yaml:"priority"`
+	Tags      []string      `yaml:"tags"`
+	Checklist []string      `yaml:"checklist"`
+	Vars      []templateVar `yaml:"vars"`
+}
+
+type templateVar struct {
+	Name    string `yaml:"name"`
+	Prompt  string `yaml:"prompt"`
+	Default string `yaml:"default"`
+}
+
+func (t taskTemplate) FilterValue() string { return t.Key }
+func (t taskTemplate) Title() string       { return t.Key }
+func (t taskTemplate) Description() string { return t.Name }
+
+// blankTemplate is offered next to the user's templates to skip them.
+var blankTemplate = taskTemplate{Key: "Blank task", Name: "Start from scratch"}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// builtinVars are filled in automatically and never prompted for.
+func builtinVars() map[string]string {
+	return map[string]string{
+		"date": time.Now().Format("2006-01-02"),
+	}
+}
+
+func templatesDir() string {
+	return filepath.Join(configDir(), "templates")
+}
+
+// loadTemplates reads every .yaml, .yml and .md template, sorted by name.
+func loadTemplates() ([]taskTemplate, error) {
+	entries, err := os.ReadDir(templatesDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var templates []taskTemplate
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".md") {
+			continue
+		}
+		t, err := readTemplate(filepath.Join(templatesDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Key < templates[j].Key })
+	return templates, nil
+}
+
+func loadTemplate(key string) (taskTemplate, error) {
+	for _, ext := range []string{".yaml", ".yml", ".md"} {
+		path := filepath.Join(templatesDir(), key+ext)
+		if _, err := os.Stat(path); err == nil {
+			return readTemplate(path)
+		}
+	}
+	return taskTemplate{}, fmt.Errorf("template %q not found in %s", key, templatesDir())
+}
+
+// readTemplate parses a YAML template, or a Markdown file whose YAML front
+// matter holds the fields and whose body is the description.
+func readTemplate(path string) (taskTemplate, error) {
+	var t taskTemplate
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+	ext := filepath.Ext(path)
+	t.Key = strings.TrimSuffix(filepath.Base(path), ext)
+
+	if ext == ".md" {
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		body := data
+		if rest, ok := bytes.CutPrefix(data, []byte("---\n")); ok {
+			front, after, ok := splitFrontMatter(rest)
+			if !ok {
+				return t, fmt.Errorf("template %s: unterminated front matter", path)
+			}
+			if err := yaml.Unmarshal(front, &t); err != nil {
+				return t, fmt.Errorf("template %s: %w", path, err)
+			}
+			body = after
+		}
+		t.Body = strings.TrimSpace(string(body))
+		return t, nil
+	}
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("template %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// splitFrontMatter splits what follows the opening --- of a Markdown
+// template at the first line holding only ---. The front matter may be empty.
+func splitFrontMatter(rest []byte) (front, body []byte, ok bool) {
+	for i := 0; i < len(rest); {
+		line, next := rest[i:], len(rest)
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line, next = line[:end], i+end+1
+		}
+		if string(bytes.TrimRight(line, " \t")) == "---" {
+			return rest[:i], rest[next:], true
+		}
+		i = next
+	}
+	return nil, nil, false
+}
+
+// variables returns the declared variables followed by any placeholder used
+// in the template without a declaration.
+func (t taskTemplate) variables() []templateVar {
+	vars := append([]templateVar{}, t.Vars...)
+	seen := map[string]bool{}
+	for name := range builtinVars() {
+		seen[name] = true
+	}
+	for _, v := range vars {
+		seen[v.Name] = true
+	}
+	texts := append([]string{t.Name, t.Body, t.Status}, t.Tags...)
+	texts = append(texts, t.Checklist...)
+	for _, text := range texts {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				vars = append(vars, templateVar{Name: match[1]})
+			}
+		}
+	}
+	return vars
+}
+
+func (v templateVar) label() string {
+	if v.Prompt != "" {
+		return v.Prompt
+	}
+	return v.Name
+}
+
+// render returns a copy of the template with all placeholders replaced.
+// Unknown placeholders are left untouched.
+func (t taskTemplate) render(values map[string]string) taskTemplate {
+	all := builtinVars()
+	for k, v := range values {
+		all[k] = v
+	}
+	expand := func(s string) string {
+		return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := placeholderPattern.FindStringSubmatch(match)[1]
+			if v, ok := all[name]; ok {
+				return v
+			}
+			return match
+		})
+	}
+	rendered := t
+	rendered.Name = expand(t.Name)
+	rendered.Body = expand(t.Body)
+	rendered.Status = expand(t.Status)
+	rendered.Tags = nil
+	for _, tag := range t.Tags {
+		rendered.Tags = append(rendered.Tags, expand(tag))
+	}
+	rendered.Checklist = nil
+	for _, item := range t.Checklist {
+		rendered.Checklist = append(rendered.Checklist, expand(item))
+	}
+	return rendered
+}
+
+// createTaskWithChecklistCmd creates a task and, when items are given, adds a
+// checklist holding them.
+func createTaskWithChecklistCmd(apiToken, listID string, fields map[string]interface{}, items []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := createTaskWithFieldsCmd(apiToken, listID, fields)()
+		task, ok := msg.(Task)
+		if !ok || len(items) == 0 {
+			return msg
+		}
+		checklist := Checklist{Name: "Checklist"}
+		for _, item := range items {
+			checklist.Items = append(checklist.Items, ChecklistItem{Name: item})
+		}
+		if err := copyChecklists(apiToken, task.ID, []Checklist{checklist}); err != nil {
+			return err
+		}
+		return task
+	}
+}
+
+// --- UPDATE & VIEW (CREATE TASK FROM TEMPLATE) ---
+func (m model) startTemplateSelection(templates []taskTemplate) model {
+	m.state = createTaskTemplateView
+	items := []list.Item{blankTemplate}
+	for _, t := range templates {
+		items = append(items, t)
+	}
+	h, v := appStyle.GetFrameSize()
+	m.templateList = newList(items, newItemDelegate(), m.width-h, m.height-v)
+	m.templateList.Title = "Start from a Template"
+	return m
+}
+
+func updateCreateTaskTemplate(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.templateList.SetSize(msg.Width-h, msg.Height-v)
+	case tea.KeyMsg:
+		if msg.String() == "enter" && m.templateList.FilterState() != list.Filtering {
+			selected, ok := m.templateList.SelectedItem().(taskTemplate)
+			if !ok {
+				break
+			}
+			if selected.Key == blankTemplate.Key {
+				if m.template != nil {
+					m = m.resetNewTask()
+				}
+				return m.startCreateTaskTitle(), nil
+			}
+			m = m.resetNewTask()
+			m.template = &selected
+			m.templateValues = map[string]string{}
+			m.templateVars = selected.variables()
+			m.templateVarIndex = 0
+			return m.nextTemplateVar()
+		}
+	}
+	m.templateList, cmd = m.templateList.Update(msg)
+	return m, cmd
+}
+
+// nextTemplateVar prompts for the next template variable, or fills in the
+// template and continues with the regular wizard once all are known.
+func (m model) nextTemplateVar() (tea.Model, tea.Cmd) {
+	if m.templateVarIndex >= len(m.templateVars) {
+		rendered := m.template.render(m.templateValues)
+		m.template = &rendered
+		m.newTaskStatus = rendered.Status
+		m.newTaskTags = rendered.Tags
+		m.newTaskChecklist = rendered.Checklist
+		if p, err := parsePriority(rendered.Priority); err == nil {
+			m.newTaskPriority = p
+		}
+		m.newTaskTitle = rendered.Name
+		m.newTaskDesc = rendered.Body
+		return m.startCreateTaskTitle(), nil
+	}
+	v := m.templateVars[m.templateVarIndex]
+	m.state = createTaskVarsView
+	m.varInput = textinput.New()
+	m.varInput.Placeholder = v.Default
+	m.varInput.Focus()
+	return m, textinput.Blink
+}
+
+func updateCreateTaskVars(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if isBackKey(msg) {
+			if m.templateVarIndex == 0 {
+				m.state = createTaskTemplateView
+				return m, nil
+			}
+			m.templateVarIndex--
+			v := m.templateVars[m.templateVarIndex]
+			m.varInput = textinput.New()
+			m.varInput.Placeholder = v.Default
+			m.varInput.SetValue(m.templateValues[v.Name])
+			m.varInput.Focus()
+			return m, textinput.Blink
+		}
+		if msg.Type == tea.KeyEnter {
+			v := m.templateVars[m.templateVarIndex]
+			value := m.varInput.Value()
+			if value == "" {
+				value = v.Default
+			}
+			m.templateValues[v.Name] = value
+			m.templateVarIndex++
+			return m.nextTemplateVar()
+		}
+	}
+	m.varInput, cmd = m.varInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewCreateTaskVars() string {
+	v := m.templateVars[m.templateVarIndex]
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Template: " + m.template.Key))
+	b.WriteString(fmt.Sprintf("\n\n%s (%d/%d):\n\n", v.label(), m.templateVarIndex+1, len(m.templateVars)))
+	b.WriteString(m.varInput.View())
+	return appStyle.Render(b.String())
+}
+
+// preselectTemplateAssignees marks the members named by the template once the
+// member list (and the current user, for "me") are known.
+func (m *model) preselectTemplateAssignees() {
+	if m.template == nil {
+		return
+	}
+	for _, name := range m.template.Assignees {
+		if strings.EqualFold(name, "me") {
+			if m.currentUser != nil {
+				m.selectedAssignees[m.currentUser.ID] = struct{}{}
+			}
+			continue
+		}
+		for _, item := range m.assigneeList.Items() {
+			member := item.(Member)
+			if fmt.Sprint(member.ID) == name || strings.EqualFold(member.Username, name) || strings.EqualFold(member.Email, name) {
+				m.selectedAssignees[member.ID] = struct{}{}
+			}
+		}
+	}
+	m.assigneeList.SetDelegate(assigneeDelegate{selected: m.selectedAssignees})
+}
+
+// --- TASK CREATE (CLI) ---
+
+var (
+	createTemplate    string
+	createVars        []string
+	createListID      string
+	createName        string
+	createDescription string
+	createStatus      string
+	createPriority    string
+	createAssignees   []string
+	createTags        []string
+	createDue         string
+	createStart       string
+)
+
+var taskCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a task, optionally from a template",
+	Example: `  clup task create --list 901234 --name "Update docs" --due "next fri 17:00"
+  clup task create --list 901234 --template bug --var component=api`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if createListID == "" {
+			fmt.Println("A list is required, use --list.")
+			os.Exit(1)
+		}
+		t := taskTemplate{}
+		if createTemplate != "" {
+			var err error
+			t, err = loadTemplate(createTemplate)
+			if err != nil {
+				fmt.Println("Error loading template:", err)
+				os.Exit(1)
+			}
+			values := map[string]string{}
+			for _, kv := range createVars {
+				k, v, ok := strings.Cut(kv, "=")
+				if !ok {
+					fmt.Printf("Invalid --var %q, expected name=value.\n", kv)
+					os.Exit(1)
+				}
+				values[k] = v
+			}
+			promptTemplateVars(t.variables(), values)
+			t = t.render(values)
+		}
+
+		if cmd.Flags().Changed("name") {
+			t.Name = createName
+		}
+		if cmd.Flags().Changed("description") {
+			t.Body = createDescription
+		}
+		if cmd.Flags().Changed("status") {
+			t.Status = createStatus
+		}
+		if cmd.Flags().Changed("priority") {
+			t.Priority = createPriority
+		}
+		t.Assignees = append(t.Assignees, createAssignees...)
+		t.Tags = append(t.Tags, createTags...)
+		if t.Name == "" {
+			fmt.Println("A task name is required, use --name or a template.")
+			os.Exit(1)
+		}
+
+		apiToken, teamID := requireCredentials()
+		members := &memberResolver{apiToken: apiToken, teamID: teamID}
+		fields, err := templateFields(t, members)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		if createDue != "" || createStart != "" {
+			loc, err := members.location()
+			if err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			due, start, err := parseDates(createDue, createStart, time.Now().In(loc))
+			if err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			if !due.isZero() {
+				setDateField(fields, "due", due)
+			}
+			if !start.isZero() {
+				setDateField(fields, "start", start)
+			}
+		}
+		switch msg := createTaskWithChecklistCmd(apiToken, createListID, fields, t.Checklist)().(type) {
+		case error:
+			fmt.Println("Error creating task:", msg)
+			os.Exit(1)
+		case Task:
+			fmt.Printf("Created task %s %s\n", msg.ID, msg.URL)
+		}
+	},
+}
+
+var taskTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the available task templates",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		templates, err := loadTemplates()
+		if err != nil {
+			fmt.Println("Error loading templates:", err)
+			os.Exit(1)
+		}
+		if len(templates) == 0 {
+			fmt.Println("No templates found in", templatesDir())
+			return
+		}
+		for _, t := range templates {
+			fmt.Printf("%-20s %s\n", t.Key, t.Name)
+		}
+	},
+}
+
+func init() {
+	f := taskCreateCmd.Flags()
+	f.StringVar(&createTemplate, "template", "", "template to start from")
+	f.StringArrayVar(&createVars, "var", nil, "template variable as name=value (repeatable)")
+	f.StringVar(&createListID, "list", "", "list to create the task in")
+	f.StringVar(&createName, "name", "", "task name")
+	f.StringVar(&createDescription, "description", "", "task description")
+	f.StringVar(&createStatus, "status", "", "task status")
+	f.StringVar(&createPriority, "priority", "", "urgent, high, normal, low or none")
+	f.StringArrayVar(&createAssignees, "assignee", nil, "assignee: me, a user ID, username or email (repeatable)")
+	f.StringArrayVar(&createTags, "tag", nil, "tag name (repeatable)")
+	f.StringVar(&createDue, "due", "", "due date, e.g. tomorrow, next fri, in 3d or \"2026-11-01 14:00\"")
+	f.StringVar(&createStart, "start", "", "start date, in the same forms as --due")
+}
+
+// promptTemplateVars asks on stdin for every variable not given in values.
+// Empty answers, and a closed stdin, fall back to the default.
+func promptTemplateVars(vars []templateVar, values map[string]string) {
+	reader := bufio.NewReader(os.Stdin)
+	for _, v := range vars {
+		if _, ok := values[v.Name]; ok {
+			continue
+		}
+		if v.Default != "" {
+			fmt.Printf("%s [%s]: ", v.label(), v.Default)
+		} else {
+			fmt.Printf("%s: ", v.label())
+		}
+		line, _ := reader.ReadString('\n')
+		if line = strings.TrimSpace(line); line == "" {
+			line = v.Default
+		}
+		values[v.Name] = line
+	}
+}
+
+// templateFields converts a rendered template into a create task payload.
+func templateFields(t taskTemplate, members *memberResolver) (map[string]interface{}, error) {
+	fields := map[string]interface{}{
+		"name":        t.Name,
+		"description": t.Body,
+	}
+	if t.Status != "" {
+		fields["status"] = t.Status
+	}
+	if t.Priority != "" {
+		p, err := parsePriority(t.Priority)
+		if err != nil {
+			return nil, err
+		}
+		if p > 0 {
+			fields["priority"] = p
+		}
+	}
+	var assignees []int
+	for _, name := range t.Assignees {
+		id, err := members.resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		assignees = append(assignees, id)
+	}
+	if len(assignees) > 0 {
+		fields["assignees"] = assignees
+	}
+	if len(t.Tags) > 0 {
+		fields["tags"] = t.Tags
+	}
+	return fields, nil
+}
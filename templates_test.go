@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadTemplate(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    taskTemplate
+		wantErr bool
+	}{
+		{
+			file:    "bug.yaml",
+			content: "name: 'Bug: {{component}}'\npriority: high\ntags: [bug]\nchecklist:\n  - Reproduce\n",
+			want:    taskTemplate{Key: "bug", Name: "Bug: {{component}}", Priority: "high", Tags: []string{"bug"}, Checklist: []string{"Reproduce"}},
+		},
+		{
+			file:    "release.md",
+			content: "---\nname: Release {{version}}\nvars:\n  - name: version\n    prompt: Version\n---\n\n## Steps\n\n- Tag\n",
+			want: taskTemplate{
+				Key: "release", Name: "Release {{version}}",
+				Vars: []templateVar{{Name: "version", Prompt: "Version"}},
+				Body: "## Steps\n\n- Tag",
+			},
+		},
+		{
+			file:    "empty.md",
+			content: "---\n---\nJust a body\n",
+			want:    taskTemplate{Key: "empty", Body: "Just a body"},
+		},
+		{
+			file:    "blank.md",
+			content: "---\n\n---\n",
+			want:    taskTemplate{Key: "blank"},
+		},
+		{
+			file:    "crlf.md",
+			content: "---\r\nname: Windows\r\n---\r\nBody\r\n",
+			want:    taskTemplate{Key: "crlf", Name: "Windows", Body: "Body"},
+		},
+		{
+			file:    "rule.md",
+			content: "---\nname: Rule\n---\nAbove\n\n---\n\nBelow\n",
+			want:    taskTemplate{Key: "rule", Name: "Rule", Body: "Above\n\n---\n\nBelow"},
+		},
+		{
+			file:    "plain.md",
+			content: "No front matter\n",
+			want:    taskTemplate{Key: "plain", Body: "No front matter"},
+		},
+		{file: "open.md", content: "---\nname: Open\n", wantErr: true},
+		{file: "invalid.yaml", content: "name: [unclosed\n", wantErr: true},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.file)
+		if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readTemplate(path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("readTemplate(%s) succeeded, want an error", tt.file)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("readTemplate(%s): %v", tt.file, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("readTemplate(%s) = %+v, want %+v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tmpl := taskTemplate{
+		Key:       "bug",
+		Name:      "Bug in {{ component }} on {{date}}",
+		Body:      "Seen by {{reporter}} in {{component}}",
+		Status:    "{{status}}",
+		Tags:      []string{"{{component}}", "bug"},
+		Checklist: []string{"Fix {{component}}"},
+	}
+	got := tmpl.render(map[string]string{"component": "api", "status": "open"})
+	today := time.Now().Format("2006-01-02")
+	want := taskTemplate{
+		Key:       "bug",
+		Name:      "Bug in api on " + today,
+		Body:      "Seen by {{reporter}} in api",
+		Status:    "open",
+		Tags:      []string{"api", "bug"},
+		Checklist: []string{"Fix api"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("render = %+v, want %+v", got, want)
+	}
+	if tmpl.Name != "Bug in {{ component }} on {{date}}" {
+		t.Errorf("render changed the template: %q", tmpl.Name)
+	}
+}
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// --- SAVED VIEWS ---
+
+// savedView is a named task list kept in the config file: a query in the
+// syntax of `task update --where` with its sort order and grouping.
+type savedView struct {
+	Filter string `yaml:"filter,omitempty"`
+	Sort   string `yaml:"sort,omitempty"`
+	Group  string `yaml:"group,omitempty"`
+}
+
+// order returns the order of the view, falling back to the configured
+// defaults.
+func (v savedView) order() taskOrder {
+	order := taskOrder{Sort: settings.Sort, Group: settings.Group}
+	if v.Sort != "" {
+		order.Sort = v.Sort
+	}
+	if v.Group != "" {
+		order.Group = v.Group
+	}
+	return order
+}
+
+// describe summarizes the view for the view picker.
+func (v savedView) describe() string {
+	filter := v.Filter
+	if filter == "" {
+		filter = "all tasks"
+	}
+	return filter + " · " + v.order().String()
+}
+
+func viewScope(name string) taskScope {
+	return taskScope{kind: scopeView, id: name, name: name}
+}
+
+// findView looks up a view by name, ignoring case when there is no exact
+// match.
+func findView(name string) (string, bool) {
+	if _, ok := settings.Views[name]; ok {
+		return name, true
+	}
+	for n := range settings.Views {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+func viewNames() []string {
+	var names []string
+	for name := range settings.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// saveView stores a view in the config file, keeping the rest of the file as
+// it is. An empty sort order or grouping is saved as "none", so that the view
+// keeps looking the same when the defaults change.
+func saveView(name string, view savedView) error {
+	if view.Sort == "" {
+		view.Sort = noOrder
+	}
+	if view.Group == "" {
+		view.Group = noOrder
+	}
+	root, err := readConfigNode()
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := node.Encode(view); err != nil {
+		return err
+	}
+	if err := setNode(root, []string{"views", name}, &node); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	if err := writeConfigFile(data); err != nil {
+		return err
+	}
+	if settings.Views == nil {
+		settings.Views = make(map[string]savedView)
+	}
+	settings.Views[name] = view
+	return nil
+}
+
+// deleteView removes a view from the config file, along with default_view
+// when it names the view.
+func deleteView(name string) error {
+	root, err := readConfigNode()
+	if err != nil {
+		return err
+	}
+	deleteNode(root, []string{"views", name})
+	if settings.DefaultView == name {
+		deleteNode(root, []string{"default_view"})
+	}
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	if err := writeConfigFile(data); err != nil {
+		return err
+	}
+	delete(settings.Views, name)
+	if settings.DefaultView == name {
+		settings.DefaultView = ""
+	}
+	return nil
+}
+
+// viewTasks loads the tasks of a view in its sort order.
+func viewTasks(apiToken, teamID, name string) ([]Task, error) {
+	view := settings.Views[name]
+	members := &memberResolver{apiToken: apiToken, teamID: teamID}
+	tasks, err := queryTasks(apiToken, teamID, view.Filter, members)
+	if err != nil {
+		return nil, err
+	}
+	sortTasks(tasks, view.order().Sort)
+	return tasks, nil
+}
+
+// fetchViewTasksCmd loads the tasks of a view for the task picker.
+func fetchViewTasksCmd(apiToken, teamID, name string) tea.Cmd {
+	return func() tea.Msg {
+		tasks, err := viewTasks(apiToken, teamID, name)
+		if err != nil {
+			return err
+		}
+		return TasksResponse{Tasks: tasks}
+	}
+}
+
+// --- UPDATE & VIEW (VIEW SELECTION) ---
+
+// When there are saved views, the TUI starts with a list of them. The space
+// tree is one more entry of the list, and esc in the tree comes back here.
+
+type viewItem struct {
+	name string
+	view savedView
+}
+
+func (i viewItem) Title() string       { return i.name }
+func (i viewItem) Description() string { return i.view.describe() }
+func (i viewItem) FilterValue() string { return i.name }
+
+// browseItem leads from the view picker to the space tree.
+type browseItem struct{}
+
+func (i browseItem) Title() string       { return "Browse spaces" }
+func (i browseItem) Description() string { return "Spaces, folders and lists" }
+func (i browseItem) FilterValue() string { return "spaces" }
+
+func (m model) startViewSelection() model {
+	m.state = viewSelectionView
+	var items []list.Item
+	for _, name := range viewNames() {
+		items = append(items, viewItem{name: name, view: settings.Views[name]})
+	}
+	items = append(items, browseItem{})
+	h, v := appStyle.GetFrameSize()
+	l := newList(items, newItemDelegate(), m.width-h, m.height-v)
+	l.Title = "Select a View"
+	if m.profile != "" {
+		l.Title = fmt.Sprintf("Select a View (%s)", m.profile)
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.SwitchWorkspace}
+	}
+	m.viewList = l
+	return m
+}
+
+func updateViewSelection(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.viewList.SetSize(msg.Width-h, msg.Height-v)
+	case error:
+		m.err = msg
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if m.viewList.FilterState() == list.Filtering {
+			break
+		}
+		if key.Matches(msg, keys.SwitchWorkspace) {
+			return m.startProfileSelection()
+		}
+		if msg.String() != "enter" {
+			break
+		}
+		switch selected := m.viewList.SelectedItem().(type) {
+		case viewItem:
+			return m.startTaskList(viewScope(selected.name))
+		case browseItem:
+			m.state = spaceSelectionView
+			h, v := appStyle.GetFrameSize()
+			m.spaceList.SetSize(m.width-h, m.height-v)
+			if len(m.tree.spaces) == 0 {
+				return m, fetchSpacesCmd(m.apiToken, m.teamID)
+			}
+			return m, nil
+		}
+	}
+	m.viewList, cmd = m.viewList.Update(msg)
+	return m, cmd
+}
+
+// --- UPDATE & VIEW (SAVE VIEW) ---
+
+func (m model) startViewName() (model, tea.Cmd) {
+	m.state = viewNameView
+	m.nameInput = textinput.New()
+	m.nameInput.Width = 50
+	m.nameInput.Placeholder = "view name"
+	if m.scope.kind == scopeView {
+		m.nameInput.SetValue(m.scope.id)
+	}
+	m.nameInput.Focus()
+	return m, textinput.Blink
+}
+
+func updateViewName(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.state = listView
+			return m, nil
+		case tea.KeyEnter:
+			name := strings.TrimSpace(m.nameInput.Value())
+			if name == "" {
+				return m, nil
+			}
+			m.state = listView
+			view := savedView{Filter: m.viewFilter(), Sort: m.order.Sort, Group: m.order.Group}
+			if err := saveView(name, view); err != nil {
+				return m, m.list.NewStatusMessage(failureStyle.Render("Could not save the view: " + err.Error()))
+			}
+			m.scope = viewScope(name)
+			m.list.Title = m.taskListTitle()
+			return m, m.list.NewStatusMessage(statusMessageStyle("Saved view " + name))
+		}
+	}
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewViewName() string {
+	filter := m.viewFilter()
+	if filter == "" {
+		filter = "all tasks"
+	}
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Save as view"))
+	b.WriteString("\n\n")
+	b.WriteString(blurredStyle.Render(filter + " · " + m.order.String()))
+	b.WriteString("\n\n")
+	b.WriteString(m.nameInput.View())
+	b.WriteString(helpStyle.Render("\n\nenter to save • esc to cancel"))
+	return appStyle.Render(b.String())
+}
+
+// viewFilter is the query of the task list shown, including the tags it is
+// filtered by.
+func (m model) viewFilter() string {
+	if len(m.tagFilter) == 0 {
+		return m.scope.where()
+	}
+	return strings.TrimSpace(m.scope.where() + " " + tagTerm(m.tagFilter))
+}
+
+// --- CLICKUP VIEWS ---
+
+// ClickUp views are imported as far as clup's queries can express them:
+// filters on status, assignee, tag and priority, the sort field and the
+// grouping. Everything else is reported and left out.
+
+type clickUpView struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Parent struct {
+		ID   string `json:"id"`
+		Type int    `json:"type"`
+	} `json:"parent"`
+	Grouping struct {
+		Field string `json:"field"`
+	} `json:"grouping"`
+	Sorting struct {
+		Fields []struct {
+			Field string `json:"field"`
+			Dir   int    `json:"dir"`
+		} `json:"fields"`
+	} `json:"sorting"`
+	Filters struct {
+		Op     string `json:"op"`
+		Fields []struct {
+			Field  string            `json:"field"`
+			Op     string            `json:"op"`
+			Values []json.RawMessage `json:"values"`
+		} `json:"fields"`
+		Search     string `json:"search"`
+		ShowClosed bool   `json:"show_closed"`
+	} `json:"filters"`
+}
+
+type clickUpViewsResponse struct {
+	Views []clickUpView `json:"views"`
+}
+
+// taskViewTypes are the ClickUp view types that show tasks.
+var taskViewTypes = map[string]bool{
+	"list": true, "board": true, "calendar": true, "gantt": true,
+	"table": true, "timeline": true, "box": true,
+}
+
+// viewParents maps ClickUp's parent types to query keys.
+var viewParents = map[int]string{4: "space", 5: "folder", 6: "list"}
+
+// viewSortFields maps ClickUp's sort fields to sort orders, along with the
+// direction clup sorts them in by default (1 ascending, -1 descending).
+var viewSortFields = map[string]struct {
+	sort string
+	dir  int
+}{
+	"priority":    {"priority", 1},
+	"dueDate":     {"due", 1},
+	"status":      {"status", 1},
+	"dateUpdated": {"updated", -1},
+	"dateCreated": {"created", -1},
+	"name":        {"name", 1},
+}
+
+// viewFilterKeys maps ClickUp's filter fields to query keys.
+var viewFilterKeys = map[string]string{
+	"status": "status", "assignee": "assignee", "tag": "tag", "priority": "priority",
+}
+
+func fetchClickUpViews(apiToken, url string) ([]clickUpView, error) {
+	body, err := sendContainerRequest(apiToken, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch views failed: %v", err)
+	}
+	var views clickUpViewsResponse
+	if err := json.Unmarshal(body, &views); err != nil {
+		return nil, err
+	}
+	return views.Views, nil
+}
+
+// convert turns a ClickUp view into a saved view, listing the parts that
+// were left out.
+func (v clickUpView) convert() (savedView, []string) {
+	var view savedView
+	var skipped, terms []string
+	if k, ok := viewParents[v.Parent.Type]; ok {
+		terms = append(terms, k+"="+v.Parent.ID)
+	}
+	if len(v.Filters.Fields) > 1 && strings.EqualFold(v.Filters.Op, "OR") {
+		skipped = append(skipped, "OR filters (imported as AND)")
+	}
+	for _, f := range v.Filters.Fields {
+		k, ok := viewFilterKeys[f.Field]
+		if !ok || (f.Op != "" && f.Op != "EQ" && f.Op != "ANY") {
+			skipped = append(skipped, strings.TrimSpace("filter "+f.Field+" "+f.Op))
+			continue
+		}
+		var values []string
+		for _, raw := range f.Values {
+			var s string
+			if json.Unmarshal(raw, &s) != nil {
+				s = string(raw)
+			}
+			values = append(values, s)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		value := strings.Join(values, ",")
+		if strings.ContainsAny(value, " \t") {
+			value = "'" + value + "'"
+		}
+		terms = append(terms, k+"="+value)
+	}
+	if v.Filters.Search != "" {
+		skipped = append(skipped, "search")
+	}
+	view.Filter = strings.Join(terms, " ")
+
+	if len(v.Sorting.Fields) > 0 {
+		f := v.Sorting.Fields[0]
+		if s, ok := viewSortFields[f.Field]; ok {
+			view.Sort = s.sort
+			if f.Dir != 0 && f.Dir != s.dir {
+				view.Sort = "-" + s.sort
+			}
+		} else {
+			skipped = append(skipped, "sort by "+f.Field)
+		}
+		if len(v.Sorting.Fields) > 1 {
+			skipped = append(skipped, "secondary sort fields")
+		}
+	}
+	switch g := v.Grouping.Field; g {
+	case "status", "assignee", "priority":
+		view.Group = g
+	case "dueDate":
+		view.Group = "due"
+	case "", "none":
+	default:
+		skipped = append(skipped, "group by "+g)
+	}
+	return view, skipped
+}
+
+// --- VIEWS (CLI) ---
+
+var (
+	viewsSpace     string
+	viewsFolder    string
+	viewsList      string
+	viewsOverwrite bool
+)
+
+var viewsCmd = &cobra.Command{
+	Use:   "views",
+	Short: "List the saved views",
+	Long: `List the views saved in the config file. Save a view with S in the task
+list, or import the views of ClickUp with ` + "`clup views import`" + `. The default
+view, marked with *, opens when clup starts.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		names := viewNames()
+		if len(names) == 0 {
+			fmt.Println("No views. Save one with S in the task list or import them with `clup views import`.")
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  NAME\tFILTER\tORDER")
+		for _, name := range names {
+			marker := " "
+			if name == settings.DefaultView {
+				marker = "*"
+			}
+			view := settings.Views[name]
+			fmt.Fprintf(w, "%s %s\t%s\t%s\n", marker, name, orDash(view.Filter), view.order())
+		}
+		w.Flush()
+	},
+}
+
+var viewsImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import the views of ClickUp",
+	Long: `Import the task views of the workspace, or of a space, folder or list, as
+saved views. Filters on status, assignee, tag and priority, the first sort
+field and grouping by status, assignee, priority or due date are kept;
+anything else is listed and left out. Views whose name is already taken are
+skipped unless --overwrite is given.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiToken, teamID := requireCredentials()
+		url := fmt.Sprintf("https://api.clickup.com/api/v2/team/%s/view", teamID)
+		if scope, ok := scopeFor(apiToken, teamID, viewsSpace, viewsFolder, viewsList); ok {
+			url = containerURL(scope) + "/view"
+		}
+		views, err := fetchClickUpViews(apiToken, url)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		imported := 0
+		for _, v := range views {
+			if !taskViewTypes[v.Type] {
+				continue
+			}
+			if _, ok := settings.Views[v.Name]; ok && !viewsOverwrite {
+				fmt.Printf("Skipped %s: a view with this name exists.\n", v.Name)
+				continue
+			}
+			view, skipped := v.convert()
+			if err := saveView(v.Name, view); err != nil {
+				fmt.Println("Error saving view:", err)
+				os.Exit(1)
+			}
+			imported++
+			if len(skipped) > 0 {
+				fmt.Printf("Imported %s without %s.\n", v.Name, strings.Join(skipped, ", "))
+			} else {
+				fmt.Printf("Imported %s.\n", v.Name)
+			}
+		}
+		if imported == 0 {
+			fmt.Println("No views imported.")
+		}
+	},
+}
+
+var viewsDeleteCmd = &cobra.Command{
+	Use:     "delete <name>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a saved view",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, ok := findView(args[0])
+		if !ok {
+			fmt.Printf("Error: view %q does not exist.\n", args[0])
+			os.Exit(1)
+		}
+		if err := deleteView(name); err != nil {
+			fmt.Println("Error saving config:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Deleted view %s.\n", name)
+	},
+}
+
+func init() {
+	viewsImportCmd.Flags().StringVar(&viewsSpace, "space", "", "import the views of this space (name or ID)")
+	viewsImportCmd.Flags().StringVar(&viewsFolder, "folder", "", "import the views of this folder (name or ID)")
+	viewsImportCmd.Flags().StringVar(&viewsList, "list", "", "import the views of this list (name or ID)")
+	viewsImportCmd.Flags().BoolVar(&viewsOverwrite, "overwrite", false, "replace saved views with the same name")
+	viewsCmd.AddCommand(viewsImportCmd, viewsDeleteCmd)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestClickUpViewConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		view    string
+		want    savedView
+		skipped []string
+	}{
+		{"empty", `{}`, savedView{}, nil},
+		{"parent and filters",
+			`{"parent":{"id":"900","type":6},"filters":{"op":"AND","fields":[
+				{"field":"status","op":"EQ","values":["in progress","review"]},
+				{"field":"assignee","op":"ANY","values":[7,8]},
+				{"field":"tag","values":["bug"]}]}}`,
+			savedView{Filter: "list=900 status='in progress,review' assignee=7,8 tag=bug"}, nil},
+		{"OR filters",
+			`{"parent":{"id":"100","type":4},"filters":{"op":"OR","fields":[
+				{"field":"status","op":"EQ","values":["open"]},
+				{"field":"priority","op":"EQ","values":[1]}]}}`,
+			savedView{Filter: "space=100 status=open priority=1"}, []string{"OR filters (imported as AND)"}},
+		{"a single filter with OR",
+			`{"filters":{"op":"OR","fields":[{"field":"status","op":"EQ","values":["open"]}]}}`,
+			savedView{Filter: "status=open"}, nil},
+		{"unsupported filters",
+			`{"parent":{"id":"1","type":7},"filters":{"fields":[
+				{"field":"status","op":"NOT","values":["closed"]},
+				{"field":"dueDate","op":"EQ","values":["today"]},
+				{"field":"tag","op":"EQ","values":[]}],"search":"login"}}`,
+			savedView{}, []string{"filter status NOT", "filter dueDate EQ", "search"}},
+		{"sort in the default direction",
+			`{"sorting":{"fields":[{"field":"dueDate","dir":1}]}}`,
+			savedView{Sort: "due"}, nil},
+		{"sort reversed",
+			`{"sorting":{"fields":[{"field":"dueDate","dir":-1}]}}`,
+			savedView{Sort: "-due"}, nil},
+		{"sort descending by default",
+			`{"sorting":{"fields":[{"field":"dateUpdated","dir":-1}]}}`,
+			savedView{Sort: "updated"}, nil},
+		{"sort reversed from descending",
+			`{"sorting":{"fields":[{"field":"dateCreated","dir":1}]}}`,
+			savedView{Sort: "-created"}, nil},
+		{"sort without a direction",
+			`{"sorting":{"fields":[{"field":"priority"}]}}`,
+			savedView{Sort: "priority"}, nil},
+		{"secondary and unknown sorts",
+			`{"sorting":{"fields":[{"field":"timeEstimate","dir":1},{"field":"name","dir":1}]}}`,
+			savedView{}, []string{"sort by timeEstimate", "secondary sort fields"}},
+		{"group by due date", `{"grouping":{"field":"dueDate"}}`, savedView{Group: "due"}, nil},
+		{"group by assignee", `{"grouping":{"field":"assignee"}}`, savedView{Group: "assignee"}, nil},
+		{"no grouping", `{"grouping":{"field":"none"}}`, savedView{}, nil},
+		{"unknown grouping", `{"grouping":{"field":"tag"}}`, savedView{}, []string{"group by tag"}},
+	}
+	for _, tt := range tests {
+		var v clickUpView
+		if err := json.Unmarshal([]byte(tt.view), &v); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		view, skipped := v.convert()
+		if view != tt.want || !reflect.DeepEqual(skipped, tt.skipped) {
+			t.Errorf("%s: convert = %+v, skipped %q, want %+v, skipped %q", tt.name, view, skipped, tt.want, tt.skipped)
+		}
+	}
+}